@@ -0,0 +1,64 @@
+package admin
+
+import "time"
+
+type AdminLoginDTORequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type SuspendDTORequest struct {
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+type RejectCatalogProductDTORequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+type UpdateRoleDTORequest struct {
+	Role string `json:"role" binding:"required,oneof=user admin"`
+}
+
+// ListFilter narrows the user and shop listings. Suspended is nil to include
+// both suspended and active accounts.
+type ListFilter struct {
+	Query     string
+	Suspended *bool
+	Page      int
+	Limit     int
+}
+
+type PageDTOResponse struct {
+	Items interface{} `json:"items"`
+	Total int64       `json:"total"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+}
+
+type AdminUserDTOResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+}
+
+type AdminShopDTOResponse struct {
+	ID              uint      `json:"id"`
+	Name            string    `json:"name"`
+	OwnerName       string    `json:"owner_name"`
+	Email           string    `json:"email"`
+	Mobile          string    `json:"mobile"`
+	Type            string    `json:"type"`
+	Address         string    `json:"address"`
+	MerchantID      *uint     `json:"merchant_id,omitempty"`
+	SubscriberCount uint      `json:"subscriber_count"`
+	CreatedAt       time.Time `json:"created_at"`
+
+	ClosedAt         *time.Time `json:"closed_at,omitempty"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+}
@@ -0,0 +1,102 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/utils"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// minPasswordLength matches the rule user registration enforces.
+const minPasswordLength = 6
+
+type bootstrapConfig struct {
+	Email    string
+	Password string
+	Name     string
+}
+
+// bootstrapConfigFromEnv reads ADMIN_EMAIL, ADMIN_PASSWORD and ADMIN_NAME. It
+// returns nil when ADMIN_EMAIL is not set.
+func bootstrapConfigFromEnv() (*bootstrapConfig, error) {
+	email := strings.ToLower(strings.TrimSpace(os.Getenv("ADMIN_EMAIL")))
+	if email == "" {
+		return nil, nil
+	}
+
+	config := &bootstrapConfig{
+		Email:    email,
+		Password: os.Getenv("ADMIN_PASSWORD"),
+		Name:     strings.TrimSpace(os.Getenv("ADMIN_NAME")),
+	}
+	if config.Password != "" && len(config.Password) < minPasswordLength {
+		return nil, fmt.Errorf("ADMIN_PASSWORD must be at least %d characters", minPasswordLength)
+	}
+	if config.Name == "" {
+		config.Name = "Administrator"
+	}
+	return config, nil
+}
+
+// Bootstrap creates the first admin. It does nothing once any admin exists,
+// suspended or not. Otherwise the user with ADMIN_EMAIL is promoted, but only
+// when its password matches ADMIN_PASSWORD, or created with ADMIN_PASSWORD
+// when there is no such user.
+func Bootstrap(db *gorm.DB) error {
+	config, err := bootstrapConfigFromEnv()
+	if err != nil || config == nil {
+		return err
+	}
+
+	repo := NewRepository(db)
+
+	admins, err := repo.CountAdmins()
+	if err != nil {
+		return err
+	}
+	if admins > 0 {
+		return nil
+	}
+
+	if config.Password == "" {
+		return errors.New("admin bootstrap: ADMIN_PASSWORD is required to create the first admin")
+	}
+
+	user, err := repo.GetUserByEmail(config.Email)
+	if err == nil {
+		// Registration does not verify email, so the address alone proves
+		// nothing about who owns the account.
+		if err := utils.CheckPasswordHash(config.Password, user.Password); err != nil {
+			return fmt.Errorf("admin bootstrap: %s already exists and its password does not match ADMIN_PASSWORD", config.Email)
+		}
+		if err := repo.SetUserRole(user.ID, models.RoleAdmin); err != nil {
+			return err
+		}
+		log.Printf("admin bootstrap: promoted %s to admin", config.Email)
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	password, err := utils.HashPassword(config.Password)
+	if err != nil {
+		return err
+	}
+
+	if err := repo.CreateUser(&models.User{
+		Name:     config.Name,
+		Email:    config.Email,
+		Password: password,
+		Role:     models.RoleAdmin,
+	}); err != nil {
+		return err
+	}
+	log.Printf("admin bootstrap: created admin %s", config.Email)
+	return nil
+}
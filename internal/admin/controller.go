@@ -0,0 +1,643 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"shop-near-u/internal/middlewares"
+	"shop-near-u/internal/models"
+	productcatlog "shop-near-u/internal/productCatlog"
+	"shop-near-u/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Controller struct {
+	service        *Service
+	catalogService *productcatlog.Service
+}
+
+func NewController(s *Service, catalog *productcatlog.Service) *Controller {
+	return &Controller{service: s, catalogService: catalog}
+}
+
+func (ctrl *Controller) Login(c *gin.Context) {
+	var dto AdminLoginDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := ctrl.service.Authenticate(&dto)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	token, err := utils.GenerateAccessToken(user.ID, models.RoleAdmin)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, "failed to generate token")
+		return
+	}
+
+	utils.SetCookie(token, 3600*24*30, c)
+
+	utils.SuccessResponse(c, http.StatusOK, "Admin logged in successfully", gin.H{
+		"user":  toUserResponse(*user),
+		"token": token,
+	})
+}
+
+func (ctrl *Controller) Logout(c *gin.Context) {
+	utils.SetCookie("", -1, c)
+
+	utils.SuccessResponse(c, http.StatusOK, "Successfully logged out", nil)
+}
+
+func (ctrl *Controller) ListUsers(c *gin.Context) {
+	filter, ok := parseListFilter(c)
+	if !ok {
+		return
+	}
+
+	users, total, err := ctrl.service.ListUsers(filter)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	items := make([]AdminUserDTOResponse, 0, len(users))
+	for _, user := range users {
+		items = append(items, toUserResponse(user))
+	}
+
+	page, limit := normalizePage(filter.Page, filter.Limit)
+	utils.SuccessResponse(c, http.StatusOK, "Users retrieved successfully", PageDTOResponse{
+		Items: items,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+func (ctrl *Controller) GetUser(c *gin.Context) {
+	userID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	user, err := ctrl.service.GetUser(userID)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User retrieved successfully", toUserResponse(*user))
+}
+
+func (ctrl *Controller) SuspendUser(c *gin.Context) {
+	userID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	var dto SuspendDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	admin, ok := adminFromContext(c)
+	if !ok {
+		return
+	}
+
+	user, err := ctrl.service.SuspendUser(admin.ID, userID, dto.Reason)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User suspended successfully", toUserResponse(*user))
+}
+
+func (ctrl *Controller) ReinstateUser(c *gin.Context) {
+	userID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	user, err := ctrl.service.ReinstateUser(userID)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User reinstated successfully", toUserResponse(*user))
+}
+
+func (ctrl *Controller) UpdateUserRole(c *gin.Context) {
+	userID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	var dto UpdateRoleDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	admin, ok := adminFromContext(c)
+	if !ok {
+		return
+	}
+
+	user, err := ctrl.service.UpdateUserRole(admin.ID, userID, dto.Role)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User role updated successfully", toUserResponse(*user))
+}
+
+func (ctrl *Controller) ListShops(c *gin.Context) {
+	filter, ok := parseListFilter(c)
+	if !ok {
+		return
+	}
+
+	shops, total, err := ctrl.service.ListShops(filter)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	items := make([]AdminShopDTOResponse, 0, len(shops))
+	for _, shop := range shops {
+		items = append(items, toShopResponse(shop))
+	}
+
+	page, limit := normalizePage(filter.Page, filter.Limit)
+	utils.SuccessResponse(c, http.StatusOK, "Shops retrieved successfully", PageDTOResponse{
+		Items: items,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+func (ctrl *Controller) GetShop(c *gin.Context) {
+	shopID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid shop ID")
+		return
+	}
+
+	shop, err := ctrl.service.GetShop(shopID)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shop retrieved successfully", toShopResponse(*shop))
+}
+
+func (ctrl *Controller) SuspendShop(c *gin.Context) {
+	shopID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid shop ID")
+		return
+	}
+
+	var dto SuspendDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	shop, err := ctrl.service.SuspendShop(shopID, dto.Reason)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shop suspended successfully", toShopResponse(*shop))
+}
+
+func (ctrl *Controller) ReinstateShop(c *gin.Context) {
+	shopID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid shop ID")
+		return
+	}
+
+	shop, err := ctrl.service.ReinstateShop(shopID)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shop reinstated successfully", toShopResponse(*shop))
+}
+
+func (ctrl *Controller) GetCatalogProduct(c *gin.Context) {
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	product, err := ctrl.catalogService.GetCatalogProduct(productID)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Catalog product retrieved successfully", product)
+}
+
+func (ctrl *Controller) GetCatalogProductRevisions(c *gin.Context) {
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	if _, err := ctrl.catalogService.GetCatalogProduct(productID); err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	revisions, err := ctrl.catalogService.GetRevisions(productID)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Catalog product revisions retrieved successfully", revisions)
+}
+
+func (ctrl *Controller) UpdateCatalogProduct(c *gin.Context) {
+	admin, ok := adminFromContext(c)
+	if !ok {
+		return
+	}
+
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	var dto productcatlog.UpdateCatalogProductDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	product, err := ctrl.catalogService.UpdateCatalogProduct(productID, &dto, productcatlog.Editor{AdminID: admin.ID})
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Catalog product updated successfully", product)
+}
+
+// RetireCatalogProduct withdraws a catalog product. Pass cascade=true to also
+// remove the shop listings that still reference it.
+func (ctrl *Controller) RetireCatalogProduct(c *gin.Context) {
+	admin, ok := adminFromContext(c)
+	if !ok {
+		return
+	}
+
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	cascade, err := strconv.ParseBool(c.DefaultQuery("cascade", "false"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid cascade")
+		return
+	}
+
+	result, err := ctrl.catalogService.RetireCatalogProduct(productID, cascade, productcatlog.Editor{AdminID: admin.ID})
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Catalog product retired successfully", result)
+}
+
+func (ctrl *Controller) AddCatalogBarcode(c *gin.Context) {
+	admin, ok := adminFromContext(c)
+	if !ok {
+		return
+	}
+
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	var dto productcatlog.AddBarcodeDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	barcode, err := ctrl.catalogService.AddBarcode(productID, &dto, productcatlog.Editor{AdminID: admin.ID})
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Barcode added successfully", barcode)
+}
+
+func (ctrl *Controller) RemoveCatalogBarcode(c *gin.Context) {
+	admin, ok := adminFromContext(c)
+	if !ok {
+		return
+	}
+
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	barcodeID, err := utils.ParseUintParam(c.Param("barcodeId"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid barcode ID")
+		return
+	}
+
+	if err := ctrl.catalogService.RemoveBarcode(productID, barcodeID, productcatlog.Editor{AdminID: admin.ID}); err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Barcode removed successfully", nil)
+}
+
+func (ctrl *Controller) ListCatalogProducts(c *gin.Context) {
+	filter, ok := parseListFilter(c)
+	if !ok {
+		return
+	}
+	page, limit := normalizePage(filter.Page, filter.Limit)
+
+	products, total, err := ctrl.catalogService.ListByStatus(c.DefaultQuery("status", models.CatalogStatusPending), page, limit)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Catalog products retrieved successfully", PageDTOResponse{
+		Items: products,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+func (ctrl *Controller) ApproveCatalogProduct(c *gin.Context) {
+	admin, ok := adminFromContext(c)
+	if !ok {
+		return
+	}
+
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	product, err := ctrl.catalogService.ApproveCatalogProduct(productID, admin.ID)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Catalog product approved successfully", product)
+}
+
+func (ctrl *Controller) RejectCatalogProduct(c *gin.Context) {
+	admin, ok := adminFromContext(c)
+	if !ok {
+		return
+	}
+
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	var dto RejectCatalogProductDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	product, err := ctrl.catalogService.RejectCatalogProduct(productID, admin.ID, dto.Reason)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Catalog product rejected successfully", product)
+}
+
+func (ctrl *Controller) ListDuplicateCatalogProducts(c *gin.Context) {
+	limit, err := utils.ParseIntParam(c.DefaultQuery("limit", strconv.Itoa(defaultPageSize)))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	_, limit = normalizePage(1, limit)
+
+	groups, err := ctrl.catalogService.ListDuplicateGroups(limit)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Duplicate catalog products retrieved successfully", groups)
+}
+
+func (ctrl *Controller) MergeCatalogProduct(c *gin.Context) {
+	admin, ok := adminFromContext(c)
+	if !ok {
+		return
+	}
+
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	var dto productcatlog.MergeCatalogProductDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := ctrl.catalogService.MergeCatalogProducts(productID, dto.IntoID, admin.ID)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Catalog products merged successfully", result)
+}
+
+// parseListFilter reads q, suspended, page and limit from the query string.
+func parseListFilter(c *gin.Context) (ListFilter, bool) {
+	filter := ListFilter{Query: c.Query("q")}
+
+	if suspended := c.Query("suspended"); suspended != "" {
+		value, err := strconv.ParseBool(suspended)
+		if err != nil {
+			utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid suspended")
+			return filter, false
+		}
+		filter.Suspended = &value
+	}
+
+	var err error
+	if filter.Page, err = utils.ParseIntParam(c.DefaultQuery("page", "1")); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid page")
+		return filter, false
+	}
+	if filter.Limit, err = utils.ParseIntParam(c.DefaultQuery("limit", strconv.Itoa(defaultPageSize))); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid limit")
+		return filter, false
+	}
+	return filter, true
+}
+
+func adminFromContext(c *gin.Context) (models.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return models.User{}, false
+	}
+
+	admin, ok := user.(models.User)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, "failed to parse user data")
+		return models.User{}, false
+	}
+	return admin, true
+}
+
+func toUserResponse(user models.User) AdminUserDTOResponse {
+	return AdminUserDTOResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+
+		SuspendedAt:      user.SuspendedAt,
+		SuspensionReason: user.SuspensionReason,
+	}
+}
+
+func toShopResponse(shop models.Shop) AdminShopDTOResponse {
+	return AdminShopDTOResponse{
+		ID:              shop.ID,
+		Name:            shop.Name,
+		OwnerName:       shop.OwnerName,
+		Email:           shop.Email,
+		Mobile:          shop.Mobile,
+		Type:            shop.Type,
+		Address:         shop.Address,
+		MerchantID:      shop.MerchantID,
+		SubscriberCount: shop.SubscriberCount,
+		CreatedAt:       shop.CreatedAt,
+
+		ClosedAt:         shop.ClosedAt,
+		SuspendedAt:      shop.SuspendedAt,
+		SuspensionReason: shop.SuspensionReason,
+	}
+}
+
+func adminErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidCredentials):
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, ErrUserNotFound),
+		errors.Is(err, ErrShopNotFound),
+		errors.Is(err, productcatlog.ErrCatalogProductNotFound),
+		errors.Is(err, productcatlog.ErrBarcodeNotFound):
+		utils.ErrorResponseSimple(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrActingOnSelf),
+		errors.Is(err, productcatlog.ErrCatalogImageNotFound),
+		errors.Is(err, utils.ErrInvalidBarcode),
+		errors.Is(err, productcatlog.ErrInvalidCatalogStatus),
+		errors.Is(err, productcatlog.ErrMergeIntoSelf):
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, productcatlog.ErrCatalogProductInUse),
+		errors.Is(err, productcatlog.ErrCatalogProductNotPending),
+		errors.Is(err, productcatlog.ErrMergeTargetNotApproved),
+		errors.Is(err, productcatlog.ErrCatalogProductRetired),
+		errors.Is(err, productcatlog.ErrBarcodeTaken):
+		utils.ErrorResponseSimple(c, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
+	repo := NewRepository(db)
+	svc := NewService(repo)
+	catalogService := productcatlog.NewService(productcatlog.NewRepository(db))
+	ctrl := NewController(svc, catalogService)
+
+	r.POST("/admin/login", ctrl.Login)
+
+	admin := r.Group("/admin")
+	admin.Use(middlewares.RequireAdminAuth(db))
+	{
+		admin.POST("/logout", ctrl.Logout)
+
+		admin.GET("/users", ctrl.ListUsers)
+		admin.GET("/users/:id", ctrl.GetUser)
+		admin.POST("/users/:id/suspend", ctrl.SuspendUser)
+		admin.POST("/users/:id/reinstate", ctrl.ReinstateUser)
+		admin.PUT("/users/:id/role", ctrl.UpdateUserRole)
+
+		admin.GET("/shops", ctrl.ListShops)
+		admin.GET("/shops/:id", ctrl.GetShop)
+		admin.POST("/shops/:id/suspend", ctrl.SuspendShop)
+		admin.POST("/shops/:id/reinstate", ctrl.ReinstateShop)
+
+		admin.GET("/catalog-products", ctrl.ListCatalogProducts)
+		admin.GET("/catalog-products/duplicates", ctrl.ListDuplicateCatalogProducts)
+		admin.POST("/catalog-products/:id/merge", ctrl.MergeCatalogProduct)
+		admin.POST("/catalog-products/:id/approve", ctrl.ApproveCatalogProduct)
+		admin.POST("/catalog-products/:id/reject", ctrl.RejectCatalogProduct)
+		admin.GET("/catalog-products/:id", ctrl.GetCatalogProduct)
+		admin.GET("/catalog-products/:id/revisions", ctrl.GetCatalogProductRevisions)
+		admin.PUT("/catalog-products/:id", ctrl.UpdateCatalogProduct)
+		admin.DELETE("/catalog-products/:id", ctrl.RetireCatalogProduct)
+		admin.POST("/catalog-products/:id/barcodes", ctrl.AddCatalogBarcode)
+		admin.DELETE("/catalog-products/:id/barcodes/:barcodeId", ctrl.RemoveCatalogBarcode)
+	}
+}
@@ -0,0 +1,119 @@
+package admin
+
+import (
+	"shop-near-u/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{DB: db}
+}
+
+func (r *Repository) ListUsers(filter ListFilter) ([]models.User, int64, error) {
+	query := r.DB.Model(&models.User{})
+	if filter.Query != "" {
+		pattern := "%" + strings.ToLower(filter.Query) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ?", pattern, pattern)
+	}
+	query = applySuspendedFilter(query, filter.Suspended)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	err := query.Order("id ASC").Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit).Find(&users).Error
+	return users, total, err
+}
+
+func (r *Repository) ListShops(filter ListFilter) ([]models.Shop, int64, error) {
+	query := r.DB.Model(&models.Shop{})
+	if filter.Query != "" {
+		pattern := "%" + strings.ToLower(filter.Query) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ? OR LOWER(owner_name) LIKE ? OR LOWER(address) LIKE ?",
+			pattern, pattern, pattern, pattern)
+	}
+	query = applySuspendedFilter(query, filter.Suspended)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var shops []models.Shop
+	err := query.Order("id ASC").Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit).Find(&shops).Error
+	return shops, total, err
+}
+
+func applySuspendedFilter(query *gorm.DB, suspended *bool) *gorm.DB {
+	if suspended == nil {
+		return query
+	}
+	if *suspended {
+		return query.Where("suspended_at IS NOT NULL")
+	}
+	return query.Where("suspended_at IS NULL")
+}
+
+func (r *Repository) GetUser(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.DB.First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *Repository) GetUserByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := r.DB.Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *Repository) CreateUser(user *models.User) error {
+	return r.DB.Create(user).Error
+}
+
+func (r *Repository) GetShop(id uint) (*models.Shop, error) {
+	var shop models.Shop
+	if err := r.DB.First(&shop, id).Error; err != nil {
+		return nil, err
+	}
+	return &shop, nil
+}
+
+// SetUserSuspension suspends the user when at is set and reinstates them when nil.
+func (r *Repository) SetUserSuspension(id uint, at *time.Time, reason string) error {
+	return r.DB.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"suspended_at":      at,
+		"suspension_reason": reason,
+	}).Error
+}
+
+// SetShopSuspension suspends the shop when at is set and reinstates it when nil.
+func (r *Repository) SetShopSuspension(id uint, at *time.Time, reason string) error {
+	return r.DB.Model(&models.Shop{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"suspended_at":      at,
+		"suspension_reason": reason,
+	}).Error
+}
+
+func (r *Repository) SetUserRole(id uint, role string) error {
+	return r.DB.Model(&models.User{}).Where("id = ?", id).Update("role", role).Error
+}
+
+// CountAdmins counts every admin account, suspended or not.
+func (r *Repository) CountAdmins() (int64, error) {
+	var count int64
+	err := r.DB.Model(&models.User{}).Where("role = ?", models.RoleAdmin).Count(&count).Error
+	return count, err
+}
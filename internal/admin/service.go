@@ -0,0 +1,169 @@
+package admin
+
+import (
+	"errors"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrShopNotFound       = errors.New("shop not found")
+	ErrActingOnSelf       = errors.New("admins cannot suspend or demote themselves")
+)
+
+type Service struct {
+	repository *Repository
+}
+
+func NewService(r *Repository) *Service {
+	return &Service{repository: r}
+}
+
+// Authenticate signs in a user who holds the admin role.
+func (s *Service) Authenticate(dto *AdminLoginDTORequest) (*models.User, error) {
+	user, err := s.repository.GetUserByEmail(dto.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if err := utils.CheckPasswordHash(dto.Password, user.Password); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if user.Role != models.RoleAdmin || user.SuspendedAt != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+func (s *Service) ListUsers(filter ListFilter) ([]models.User, int64, error) {
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+	return s.repository.ListUsers(filter)
+}
+
+func (s *Service) ListShops(filter ListFilter) ([]models.Shop, int64, error) {
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+	return s.repository.ListShops(filter)
+}
+
+func (s *Service) GetUser(id uint) (*models.User, error) {
+	user, err := s.repository.GetUser(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *Service) GetShop(id uint) (*models.Shop, error) {
+	shop, err := s.repository.GetShop(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShopNotFound
+		}
+		return nil, err
+	}
+	return shop, nil
+}
+
+func (s *Service) SuspendUser(adminID uint, userID uint, reason string) (*models.User, error) {
+	if adminID == userID {
+		return nil, ErrActingOnSelf
+	}
+	return s.setUserSuspension(userID, true, reason)
+}
+
+func (s *Service) ReinstateUser(userID uint) (*models.User, error) {
+	return s.setUserSuspension(userID, false, "")
+}
+
+func (s *Service) setUserSuspension(userID uint, suspended bool, reason string) (*models.User, error) {
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var at *time.Time
+	if suspended {
+		now := time.Now()
+		at = &now
+	}
+	if err := s.repository.SetUserSuspension(userID, at, reason); err != nil {
+		return nil, err
+	}
+	user.SuspendedAt, user.SuspensionReason = at, reason
+	return user, nil
+}
+
+func (s *Service) SuspendShop(shopID uint, reason string) (*models.Shop, error) {
+	return s.setShopSuspension(shopID, true, reason)
+}
+
+func (s *Service) ReinstateShop(shopID uint) (*models.Shop, error) {
+	return s.setShopSuspension(shopID, false, "")
+}
+
+func (s *Service) setShopSuspension(shopID uint, suspended bool, reason string) (*models.Shop, error) {
+	shop, err := s.GetShop(shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	var at *time.Time
+	if suspended {
+		now := time.Now()
+		at = &now
+	}
+	if err := s.repository.SetShopSuspension(shopID, at, reason); err != nil {
+		return nil, err
+	}
+	shop.SuspendedAt, shop.SuspensionReason = at, reason
+	return shop, nil
+}
+
+// UpdateUserRole promotes a user to admin or demotes an admin. Admins cannot
+// demote themselves, which also keeps at least one admin around.
+func (s *Service) UpdateUserRole(adminID uint, userID uint, role string) (*models.User, error) {
+	if adminID == userID && role != models.RoleAdmin {
+		return nil, ErrActingOnSelf
+	}
+
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.SetUserRole(userID, role); err != nil {
+		return nil, err
+	}
+	user.Role = role
+	return user, nil
+}
+
+// normalizePage fills in defaults and keeps the page size within bounds.
+func normalizePage(page int, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return page, limit
+}
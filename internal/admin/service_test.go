@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePage(t *testing.T) {
+	page, limit := normalizePage(0, 0)
+	assert.Equal(t, 1, page)
+	assert.Equal(t, defaultPageSize, limit)
+
+	page, limit = normalizePage(3, 500)
+	assert.Equal(t, 3, page)
+	assert.Equal(t, maxPageSize, limit)
+}
+
+func TestBootstrapConfigFromEnv(t *testing.T) {
+	t.Setenv("ADMIN_EMAIL", "")
+	config, err := bootstrapConfigFromEnv()
+	require.NoError(t, err)
+	assert.Nil(t, config)
+
+	t.Setenv("ADMIN_EMAIL", " Root@Example.com ")
+	t.Setenv("ADMIN_PASSWORD", "secret123")
+	t.Setenv("ADMIN_NAME", "")
+	config, err = bootstrapConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "root@example.com", config.Email)
+	assert.Equal(t, "Administrator", config.Name)
+
+	t.Setenv("ADMIN_PASSWORD", "abc")
+	_, err = bootstrapConfigFromEnv()
+	assert.Error(t, err)
+}
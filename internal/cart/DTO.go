@@ -0,0 +1,47 @@
+package cart
+
+import "shop-near-u/internal/order"
+
+type AddCartItemDTORequest struct {
+	ShopProductID uint `json:"shop_product_id" binding:"required"`
+	Quantity      int  `json:"quantity" binding:"required,gt=0"`
+}
+
+type UpdateCartItemDTORequest struct {
+	Quantity int `json:"quantity" binding:"required,gt=0"`
+}
+
+type CheckoutDTORequest struct {
+	FulfilmentType  string                    `json:"fulfilment_type" binding:"omitempty,oneof=pickup delivery"`
+	DeliveryAddress *order.DeliveryAddressDTO `json:"delivery_address"`
+}
+
+type CartLineDTOResponse struct {
+	ID             uint    `json:"id"`
+	ShopProductID  uint    `json:"shop_product_id"`
+	CatalogID      uint    `json:"catalog_id"`
+	Name           string  `json:"name"`
+	Brand          string  `json:"brand"`
+	ImageURL       string  `json:"image_url"`
+	Quantity       int     `json:"quantity"`
+	Price          float64 `json:"price"`
+	Discount       float64 `json:"discount"`
+	EffectivePrice float64 `json:"effective_price"`
+	LineTotal      float64 `json:"line_total"`
+	Stock          int     `json:"stock"`
+	IsAvailable    bool    `json:"is_available"`
+	OverStock      bool    `json:"over_stock"`
+}
+
+type CartShopDTOResponse struct {
+	ShopID   uint                  `json:"shop_id"`
+	ShopName string                `json:"shop_name"`
+	Items    []CartLineDTOResponse `json:"items"`
+	Total    float64               `json:"total"`
+}
+
+type CartDTOResponse struct {
+	Shops     []CartShopDTOResponse `json:"shops"`
+	Total     float64               `json:"total"`
+	HasIssues bool                  `json:"has_issues"`
+}
@@ -0,0 +1,204 @@
+package cart
+
+import (
+	"errors"
+	"net/http"
+	"shop-near-u/internal/middlewares"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/order"
+	"shop-near-u/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Controller struct {
+	service *Service
+}
+
+func NewController(s *Service) *Controller {
+	return &Controller{service: s}
+}
+
+func (ctrl *Controller) GetCart(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	cart, err := ctrl.service.GetCart(u.ID)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Cart retrieved successfully", cart)
+}
+
+func (ctrl *Controller) AddItem(c *gin.Context) {
+	var dto AddCartItemDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	if err := ctrl.service.AddItem(&dto, u.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponseSimple(c, http.StatusNotFound, "product not found")
+			return
+		}
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Item added to cart", nil)
+}
+
+func (ctrl *Controller) UpdateItem(c *gin.Context) {
+	itemID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid cart item ID")
+		return
+	}
+
+	var dto UpdateCartItemDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	if err := ctrl.service.UpdateItem(itemID, u.ID, &dto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponseSimple(c, http.StatusNotFound, "cart item not found")
+			return
+		}
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Cart item updated successfully", nil)
+}
+
+func (ctrl *Controller) RemoveItem(c *gin.Context) {
+	itemID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid cart item ID")
+		return
+	}
+
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	if err := ctrl.service.RemoveItem(itemID, u.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponseSimple(c, http.StatusNotFound, "cart item not found")
+			return
+		}
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Cart item removed successfully", nil)
+}
+
+func (ctrl *Controller) Clear(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	if err := ctrl.service.Clear(u.ID); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Cart cleared successfully", nil)
+}
+
+func (ctrl *Controller) Checkout(c *gin.Context) {
+	var dto CheckoutDTORequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&dto); err != nil {
+			utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	orders, err := ctrl.service.Checkout(u.ID, &dto)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrCartEmpty):
+			utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, ErrCartHasIssues), errors.Is(err, order.ErrInsufficientStock):
+			utils.ErrorResponseSimple(c, http.StatusConflict, err.Error())
+		case errors.Is(err, order.ErrProductUnavailable), errors.Is(err, order.ErrProductNotInShop),
+			errors.Is(err, order.ErrDeliveryNotSupported), errors.Is(err, order.ErrDeliveryAddressRequired),
+			errors.Is(err, order.ErrOutsideDeliveryRadius):
+			utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		default:
+			utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Checkout completed successfully", orders)
+}
+
+func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
+	repo := NewRepository(db)
+	orderService := order.NewService(order.NewRepository(db))
+	svc := NewService(repo, orderService)
+	ctrl := NewController(svc)
+
+	cart := r.Group("/cart")
+	cart.Use(middlewares.RequireUserAuth(db))
+	{
+		cart.GET("", ctrl.GetCart)
+		cart.DELETE("", ctrl.Clear)
+		cart.POST("/items", ctrl.AddItem)
+		cart.PUT("/items/:id", ctrl.UpdateItem)
+		cart.DELETE("/items/:id", ctrl.RemoveItem)
+		cart.POST("/checkout", ctrl.Checkout)
+	}
+}
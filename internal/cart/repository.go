@@ -0,0 +1,129 @@
+package cart
+
+import (
+	"fmt"
+	"shop-near-u/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{DB: db}
+}
+
+func (r *Repository) GetItems(userID uint) ([]models.CartItem, error) {
+	return getItems(r.DB, userID)
+}
+
+func getItems(db *gorm.DB, userID uint) ([]models.CartItem, error) {
+	var items []models.CartItem
+	result := db.
+		Preload("ShopProduct.CatalogProduct").
+		Preload("ShopProduct.Shop").
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&items)
+	return items, result.Error
+}
+
+// AddItem inserts the line or, when the product is already in the cart, adds
+// the quantity to the existing line.
+func (r *Repository) AddItem(item *models.CartItem) error {
+	var product models.ShopProduct
+	if err := r.DB.First(&product, item.ShopProductID).Error; err != nil {
+		return fmt.Errorf("product with ID %d not found: %w", item.ShopProductID, err)
+	}
+
+	return r.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "shop_product_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"quantity":   gorm.Expr("cart_items.quantity + EXCLUDED.quantity"),
+			"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(item).Error
+}
+
+func (r *Repository) UpdateQuantity(itemID uint, userID uint, quantity int) error {
+	result := r.DB.Model(&models.CartItem{}).
+		Where("id = ? AND user_id = ?", itemID, userID).
+		Update("quantity", quantity)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *Repository) DeleteItem(itemID uint, userID uint) error {
+	result := r.DB.Where("id = ? AND user_id = ?", itemID, userID).Delete(&models.CartItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Checkout locks and reads the user's cart, hands the lines to place to
+// create the orders with the same transaction and then deletes exactly those
+// lines. An AddItem that arrives meanwhile waits for the lock, so its
+// quantity is neither ordered twice nor lost.
+func (r *Repository) Checkout(userID uint, place func(tx *gorm.DB, items []models.CartItem) ([]*models.Order, error)) ([]*models.Order, error) {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	var locked []uint
+	if err := tx.Model(&models.CartItem{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).Pluck("id", &locked).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	items, err := getItems(tx, userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	orders, err := place(tx, items)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	itemIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		itemIDs = append(itemIDs, item.ID)
+	}
+	if len(itemIDs) > 0 {
+		if err := tx.Where("id IN ? AND user_id = ?", itemIDs, userID).Delete(&models.CartItem{}).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to clear checked out items: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *Repository) Clear(userID uint) error {
+	return r.DB.Where("user_id = ?", userID).Delete(&models.CartItem{}).Error
+}
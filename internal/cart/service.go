@@ -0,0 +1,143 @@
+package cart
+
+import (
+	"errors"
+	"math"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/order"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCartEmpty     = errors.New("cart is empty")
+	ErrCartHasIssues = errors.New("cart has unavailable or over-stock items")
+)
+
+type Service struct {
+	repository   *Repository
+	orderService *order.Service
+}
+
+func NewService(r *Repository, o *order.Service) *Service {
+	return &Service{repository: r, orderService: o}
+}
+
+// GetCart re-prices every line against the current ShopProduct row and groups
+// the lines by shop.
+func (s *Service) GetCart(userID uint) (*CartDTOResponse, error) {
+	items, err := s.repository.GetItems(userID)
+	if err != nil {
+		return nil, err
+	}
+	return buildCart(items), nil
+}
+
+func (s *Service) AddItem(dto *AddCartItemDTORequest, userID uint) error {
+	item := &models.CartItem{
+		UserID:        userID,
+		ShopProductID: dto.ShopProductID,
+		Quantity:      dto.Quantity,
+	}
+	return s.repository.AddItem(item)
+}
+
+func (s *Service) UpdateItem(itemID uint, userID uint, dto *UpdateCartItemDTORequest) error {
+	return s.repository.UpdateQuantity(itemID, userID, dto.Quantity)
+}
+
+func (s *Service) RemoveItem(itemID uint, userID uint) error {
+	return s.repository.DeleteItem(itemID, userID)
+}
+
+func (s *Service) Clear(userID uint) error {
+	return s.repository.Clear(userID)
+}
+
+// Checkout places one order per shop in the cart and removes the ordered
+// lines in one transaction. Nothing is ordered while any line is unavailable
+// or over stock. Delivery details, when given, apply to every shop's order.
+func (s *Service) Checkout(userID uint, dto *CheckoutDTORequest) ([]*models.Order, error) {
+	return s.repository.Checkout(userID, func(tx *gorm.DB, items []models.CartItem) ([]*models.Order, error) {
+		if len(items) == 0 {
+			return nil, ErrCartEmpty
+		}
+
+		cart := buildCart(items)
+		if cart.HasIssues {
+			return nil, ErrCartHasIssues
+		}
+
+		return s.orderService.PlaceOrdersTx(tx, checkoutRequests(cart, dto), userID)
+	})
+}
+
+// checkoutRequests turns each shop in the cart into an order request.
+func checkoutRequests(cart *CartDTOResponse, dto *CheckoutDTORequest) []order.PlaceOrderDTORequest {
+	requests := make([]order.PlaceOrderDTORequest, 0, len(cart.Shops))
+	for _, shop := range cart.Shops {
+		request := order.PlaceOrderDTORequest{
+			ShopID:          shop.ShopID,
+			FulfilmentType:  dto.FulfilmentType,
+			DeliveryAddress: dto.DeliveryAddress,
+		}
+		for _, line := range shop.Items {
+			request.Items = append(request.Items, order.PlaceOrderItemDTO{
+				ShopProductID: line.ShopProductID,
+				Quantity:      line.Quantity,
+			})
+		}
+		requests = append(requests, request)
+	}
+	return requests
+}
+
+func buildCart(items []models.CartItem) *CartDTOResponse {
+	cart := &CartDTOResponse{Shops: []CartShopDTOResponse{}}
+	shopIndex := make(map[uint]int)
+
+	for _, item := range items {
+		product := item.ShopProduct
+
+		line := CartLineDTOResponse{
+			ID:             item.ID,
+			ShopProductID:  product.ID,
+			CatalogID:      product.CatalogID,
+			Name:           product.CatalogProduct.Name,
+			Brand:          product.CatalogProduct.Brand,
+			ImageURL:       product.CatalogProduct.ImageURL,
+			Quantity:       item.Quantity,
+			Price:          product.Price,
+			Discount:       product.Discount,
+			EffectivePrice: product.EffectivePrice(),
+			LineTotal:      roundPrice(product.EffectivePrice() * float64(item.Quantity)),
+			Stock:          product.Stock,
+			IsAvailable:    product.IsAvailable,
+			OverStock:      item.Quantity > product.Stock,
+		}
+
+		if !line.IsAvailable || line.OverStock {
+			cart.HasIssues = true
+		}
+
+		idx, ok := shopIndex[product.ShopID]
+		if !ok {
+			idx = len(cart.Shops)
+			shopIndex[product.ShopID] = idx
+			cart.Shops = append(cart.Shops, CartShopDTOResponse{
+				ShopID:   product.ShopID,
+				ShopName: product.Shop.Name,
+			})
+		}
+
+		cart.Shops[idx].Items = append(cart.Shops[idx].Items, line)
+		cart.Shops[idx].Total = roundPrice(cart.Shops[idx].Total + line.LineTotal)
+		cart.Total = roundPrice(cart.Total + line.LineTotal)
+	}
+
+	return cart
+}
+
+func roundPrice(value float64) float64 {
+	return math.Round(value*100) / 100
+}
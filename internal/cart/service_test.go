@@ -0,0 +1,52 @@
+package cart
+
+import (
+	"shop-near-u/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCartGroupsByShopAndFlagsIssues(t *testing.T) {
+	items := []models.CartItem{
+		{
+			ID:       1,
+			Quantity: 2,
+			ShopProduct: models.ShopProduct{
+				ID: 10, ShopID: 1, Price: 100, Discount: 10, Stock: 5, IsAvailable: true,
+				Shop: models.Shop{Name: "Corner Store"},
+			},
+		},
+		{
+			ID:       2,
+			Quantity: 3,
+			ShopProduct: models.ShopProduct{
+				ID: 20, ShopID: 2, Price: 40, Stock: 1, IsAvailable: true,
+				Shop: models.Shop{Name: "Daily Mart"},
+			},
+		},
+		{
+			ID:       3,
+			Quantity: 1,
+			ShopProduct: models.ShopProduct{
+				ID: 11, ShopID: 1, Price: 15.5, Stock: 9, IsAvailable: false,
+				Shop: models.Shop{Name: "Corner Store"},
+			},
+		},
+	}
+
+	cart := buildCart(items)
+
+	require.Len(t, cart.Shops, 2)
+	assert.Equal(t, uint(1), cart.Shops[0].ShopID)
+	assert.Len(t, cart.Shops[0].Items, 2)
+	assert.Equal(t, 90.0, cart.Shops[0].Items[0].EffectivePrice)
+	assert.Equal(t, 180.0, cart.Shops[0].Items[0].LineTotal)
+	assert.Equal(t, 195.5, cart.Shops[0].Total)
+	assert.False(t, cart.Shops[0].Items[1].IsAvailable)
+
+	assert.True(t, cart.Shops[1].Items[0].OverStock)
+	assert.Equal(t, 315.5, cart.Total)
+	assert.True(t, cart.HasIssues)
+}
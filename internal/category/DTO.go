@@ -0,0 +1,42 @@
+package category
+
+import "shop-near-u/internal/models"
+
+type CreateCategoryDTORequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// Slug defaults to the slugified name.
+	Slug     string `json:"slug" binding:"max=120"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+// UpdateCategoryDTORequest changes only the fields that are present. A
+// ParentID of 0 moves the category to the top level.
+type UpdateCategoryDTORequest struct {
+	Name     *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Slug     *string `json:"slug" binding:"omitempty,min=1,max=120"`
+	ParentID *uint   `json:"parent_id"`
+}
+
+// CategoryTreeDTOResponse is a category with its sub-categories nested below it.
+type CategoryTreeDTOResponse struct {
+	ID       uint                      `json:"id"`
+	Name     string                    `json:"name"`
+	Slug     string                    `json:"slug"`
+	Children []CategoryTreeDTOResponse `json:"children"`
+}
+
+// CategoryDetailDTOResponse is a category with the breadcrumb leading to it,
+// root first, and its direct sub-categories.
+type CategoryDetailDTOResponse struct {
+	Category  *models.Category  `json:"category"`
+	Ancestors []models.Category `json:"ancestors"`
+	Children  []models.Category `json:"children"`
+}
+
+type CategoryProductsDTOResponse struct {
+	Category *models.Category        `json:"category"`
+	Items    []models.CatalogProduct `json:"items"`
+	Total    int64                   `json:"total"`
+	Page     int                     `json:"page"`
+	Limit    int                     `json:"limit"`
+}
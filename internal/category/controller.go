@@ -0,0 +1,151 @@
+package category
+
+import (
+	"errors"
+	"net/http"
+	"shop-near-u/internal/middlewares"
+	"shop-near-u/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Controller struct {
+	service *Service
+}
+
+func NewController(s *Service) *Controller {
+	return &Controller{service: s}
+}
+
+func (ctrl *Controller) GetTree(c *gin.Context) {
+	tree, err := ctrl.service.GetTree()
+	if err != nil {
+		categoryErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Categories retrieved successfully", tree)
+}
+
+func (ctrl *Controller) GetCategory(c *gin.Context) {
+	detail, err := ctrl.service.GetCategory(c.Param("slug"))
+	if err != nil {
+		categoryErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Category retrieved successfully", detail)
+}
+
+func (ctrl *Controller) ListProducts(c *gin.Context) {
+	page, err := utils.ParseIntParam(c.DefaultQuery("page", "1"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid page")
+		return
+	}
+	limit, err := utils.ParseIntParam(c.DefaultQuery("limit", strconv.Itoa(defaultPageSize)))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	products, err := ctrl.service.ListProducts(c.Param("slug"), page, limit)
+	if err != nil {
+		categoryErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Category products retrieved successfully", products)
+}
+
+func (ctrl *Controller) CreateCategory(c *gin.Context) {
+	var dto CreateCategoryDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	category, err := ctrl.service.CreateCategory(&dto)
+	if err != nil {
+		categoryErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Category created successfully", category)
+}
+
+func (ctrl *Controller) UpdateCategory(c *gin.Context) {
+	categoryID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid category ID")
+		return
+	}
+
+	var dto UpdateCategoryDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	category, err := ctrl.service.UpdateCategory(categoryID, &dto)
+	if err != nil {
+		categoryErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Category updated successfully", category)
+}
+
+func (ctrl *Controller) DeleteCategory(c *gin.Context) {
+	categoryID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid category ID")
+		return
+	}
+
+	if err := ctrl.service.DeleteCategory(categoryID); err != nil {
+		categoryErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Category deleted successfully", nil)
+}
+
+func categoryErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrCategoryNotFound):
+		utils.ErrorResponseSimple(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrParentNotFound),
+		errors.Is(err, ErrInvalidSlug),
+		errors.Is(err, ErrCategoryCycle):
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrSlugTaken),
+		errors.Is(err, ErrCategoryHasChildren):
+		utils.ErrorResponseSimple(c, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
+	repo := NewRepository(db)
+	svc := NewService(repo)
+	ctrl := NewController(svc)
+
+	categoryGroup := r.Group("/api/categories")
+	{
+		categoryGroup.GET("", ctrl.GetTree)
+		categoryGroup.GET("/:slug", ctrl.GetCategory)
+		categoryGroup.GET("/:slug/products", ctrl.ListProducts)
+	}
+
+	adminGroup := r.Group("/admin/categories")
+	adminGroup.Use(middlewares.RequireAdminAuth(db))
+	{
+		adminGroup.POST("", ctrl.CreateCategory)
+		adminGroup.PUT("/:id", ctrl.UpdateCategory)
+		adminGroup.DELETE("/:id", ctrl.DeleteCategory)
+	}
+}
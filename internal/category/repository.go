@@ -0,0 +1,186 @@
+package category
+
+import (
+	"shop-near-u/internal/models"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{DB: db}
+}
+
+func (r *Repository) List() ([]models.Category, error) {
+	var categories []models.Category
+	err := r.DB.Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+func (r *Repository) GetByID(id uint) (*models.Category, error) {
+	var category models.Category
+	if err := r.DB.First(&category, id).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *Repository) GetBySlug(slug string) (*models.Category, error) {
+	var category models.Category
+	if err := r.DB.Where("slug = ?", slug).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// SlugTaken reports whether a category other than exceptID uses slug.
+func (r *Repository) SlugTaken(slug string, exceptID uint) (bool, error) {
+	var count int64
+	err := r.DB.Model(&models.Category{}).Where("slug = ? AND id <> ?", slug, exceptID).Count(&count).Error
+	return count > 0, err
+}
+
+// GetAncestors returns the categories above category, root first.
+func (r *Repository) GetAncestors(category *models.Category) ([]models.Category, error) {
+	var ids []uint
+	for _, part := range strings.Split(strings.Trim(category.Path, "/"), "/") {
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err == nil && uint(id) != category.ID {
+			ids = append(ids, uint(id))
+		}
+	}
+
+	ancestors := []models.Category{}
+	if len(ids) == 0 {
+		return ancestors, nil
+	}
+	err := r.DB.Where("id IN ?", ids).Order("LENGTH(path) ASC").Find(&ancestors).Error
+	return ancestors, err
+}
+
+func (r *Repository) GetChildren(id uint) ([]models.Category, error) {
+	children := []models.Category{}
+	err := r.DB.Where("parent_id = ?", id).Order("name ASC").Find(&children).Error
+	return children, err
+}
+
+func (r *Repository) HasChildren(id uint) (bool, error) {
+	var count int64
+	err := r.DB.Model(&models.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// Create inserts the category and then fills in its path, which needs the
+// new ID.
+func (r *Repository) Create(category *models.Category, parentPath string) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Create(category).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	category.Path = models.CategoryPath(parentPath, category.ID)
+	if err := tx.Model(category).Update("path", category.Path).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Update saves the category. When it moved, oldPath is its previous path and
+// every descendant's path is rewritten to hang off the new one.
+func (r *Repository) Update(category *models.Category, oldPath string) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Model(category).Select("name", "slug", "parent_id", "path").Updates(category).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if category.Path != oldPath {
+		if err := tx.Model(&models.Category{}).
+			Where("path LIKE ? AND id <> ?", oldPath+"%", category.ID).
+			Update("path", gorm.Expr("? || SUBSTRING(path FROM ?)", category.Path, len(oldPath)+1)).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// Delete removes a leaf category. Catalog products and shops filed under it
+// move up to its parent, or become uncategorised at the top level.
+func (r *Repository) Delete(category *models.Category) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Model(&models.CatalogProduct{}).Where("category_id = ?", category.ID).
+		Update("category_id", category.ParentID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Model(&models.Shop{}).Where("category_id = ?", category.ID).
+		Update("category_id", category.ParentID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(category).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// ListProducts pages through the approved, unretired catalog products filed
+// under the category with the given slug or any of its descendants.
+func (r *Repository) ListProducts(slug string, page int, limit int) ([]models.CatalogProduct, int64, error) {
+	query := r.DB.Model(&models.CatalogProduct{}).
+		Where("category_id IN ("+models.CategorySubtreeSQL+")", slug).
+		Where("status = ? AND retired_at IS NULL", models.CatalogStatusApproved)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	products := []models.CatalogProduct{}
+	err := query.Order("name ASC").Offset((page - 1) * limit).Limit(limit).Find(&products).Error
+	return products, total, err
+}
@@ -0,0 +1,270 @@
+package category
+
+import (
+	"errors"
+	"shop-near-u/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrParentNotFound      = errors.New("parent category not found")
+	ErrInvalidSlug         = errors.New("slug must be lower-case letters and digits separated by single hyphens")
+	ErrSlugTaken           = errors.New("slug is already used by another category")
+	ErrCategoryCycle       = errors.New("a category cannot be moved under itself or one of its descendants")
+	ErrCategoryHasChildren = errors.New("category has sub-categories, move or delete them first")
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type Service struct {
+	repository *Repository
+}
+
+func NewService(r *Repository) *Service {
+	return &Service{repository: r}
+}
+
+// GetTree returns every category nested under its parent, roots first.
+func (s *Service) GetTree() ([]CategoryTreeDTOResponse, error) {
+	categories, err := s.repository.List()
+	if err != nil {
+		return nil, err
+	}
+	return buildTree(categories), nil
+}
+
+// buildTree nests categories under their parents, keeping the input order
+// among siblings. Categories whose parent is missing are treated as roots.
+func buildTree(categories []models.Category) []CategoryTreeDTOResponse {
+	present := make(map[uint]bool, len(categories))
+	for _, category := range categories {
+		present[category.ID] = true
+	}
+
+	children := make(map[uint][]models.Category)
+	var roots []models.Category
+	for _, category := range categories {
+		if category.ParentID == nil || !present[*category.ParentID] {
+			roots = append(roots, category)
+			continue
+		}
+		children[*category.ParentID] = append(children[*category.ParentID], category)
+	}
+
+	var build func([]models.Category) []CategoryTreeDTOResponse
+	build = func(nodes []models.Category) []CategoryTreeDTOResponse {
+		tree := make([]CategoryTreeDTOResponse, 0, len(nodes))
+		for _, node := range nodes {
+			tree = append(tree, CategoryTreeDTOResponse{
+				ID:       node.ID,
+				Name:     node.Name,
+				Slug:     node.Slug,
+				Children: build(children[node.ID]),
+			})
+		}
+		return tree
+	}
+	return build(roots)
+}
+
+func (s *Service) GetCategory(slug string) (*CategoryDetailDTOResponse, error) {
+	category, err := s.findBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors, err := s.repository.GetAncestors(category)
+	if err != nil {
+		return nil, err
+	}
+	children, err := s.repository.GetChildren(category.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CategoryDetailDTOResponse{
+		Category:  category,
+		Ancestors: ancestors,
+		Children:  children,
+	}, nil
+}
+
+// ListProducts pages through the approved catalog products in the category
+// and all of its sub-categories.
+func (s *Service) ListProducts(slug string, page int, limit int) (*CategoryProductsDTOResponse, error) {
+	category, err := s.findBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	page, limit = normalizePage(page, limit)
+	products, total, err := s.repository.ListProducts(category.Slug, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CategoryProductsDTOResponse{
+		Category: category,
+		Items:    products,
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
+	}, nil
+}
+
+func (s *Service) CreateCategory(dto *CreateCategoryDTORequest) (*models.Category, error) {
+	slug := dto.Slug
+	if slug == "" {
+		slug = models.Slugify(dto.Name)
+	}
+	if err := s.checkSlug(slug, 0); err != nil {
+		return nil, err
+	}
+
+	category := &models.Category{
+		Name: strings.TrimSpace(dto.Name),
+		Slug: slug,
+	}
+
+	var parentPath string
+	if dto.ParentID != nil {
+		parent, err := s.findParent(*dto.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		category.ParentID = &parent.ID
+		parentPath = parent.Path
+	}
+
+	if err := s.repository.Create(category, parentPath); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// UpdateCategory renames a category or moves it, together with everything
+// beneath it, under a new parent.
+func (s *Service) UpdateCategory(id uint, dto *UpdateCategoryDTORequest) (*models.Category, error) {
+	category, err := s.findByID(id)
+	if err != nil {
+		return nil, err
+	}
+	oldPath := category.Path
+
+	if dto.Name != nil {
+		category.Name = strings.TrimSpace(*dto.Name)
+	}
+	if dto.Slug != nil && *dto.Slug != category.Slug {
+		if err := s.checkSlug(*dto.Slug, category.ID); err != nil {
+			return nil, err
+		}
+		category.Slug = *dto.Slug
+	}
+
+	if dto.ParentID != nil {
+		if *dto.ParentID == 0 {
+			category.ParentID = nil
+			category.Path = models.CategoryPath("", category.ID)
+		} else {
+			parent, err := s.findParent(*dto.ParentID)
+			if err != nil {
+				return nil, err
+			}
+			if err := checkMove(category, parent); err != nil {
+				return nil, err
+			}
+			category.ParentID = &parent.ID
+			category.Path = models.CategoryPath(parent.Path, category.ID)
+		}
+	}
+
+	if err := s.repository.Update(category, oldPath); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// checkMove refuses to put a category under itself or its own descendant,
+// which would cut the subtree off from the root.
+func checkMove(category *models.Category, parent *models.Category) error {
+	if strings.HasPrefix(parent.Path, category.Path) {
+		return ErrCategoryCycle
+	}
+	return nil
+}
+
+// DeleteCategory removes a category without sub-categories. Its products
+// and shops are re-filed under the parent.
+func (s *Service) DeleteCategory(id uint) error {
+	category, err := s.findByID(id)
+	if err != nil {
+		return err
+	}
+
+	hasChildren, err := s.repository.HasChildren(category.ID)
+	if err != nil {
+		return err
+	}
+	if hasChildren {
+		return ErrCategoryHasChildren
+	}
+
+	return s.repository.Delete(category)
+}
+
+func (s *Service) checkSlug(slug string, exceptID uint) error {
+	if slug == "" || models.Slugify(slug) != slug {
+		return ErrInvalidSlug
+	}
+	taken, err := s.repository.SlugTaken(slug, exceptID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrSlugTaken
+	}
+	return nil
+}
+
+func (s *Service) findByID(id uint) (*models.Category, error) {
+	category, err := s.repository.GetByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+func (s *Service) findBySlug(slug string) (*models.Category, error) {
+	category, err := s.repository.GetBySlug(slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+func (s *Service) findParent(id uint) (*models.Category, error) {
+	parent, err := s.repository.GetByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrParentNotFound
+	}
+	return parent, err
+}
+
+func normalizePage(page int, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return page, limit
+}
@@ -0,0 +1,36 @@
+package category
+
+import (
+	"shop-near-u/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildTree(t *testing.T) {
+	food, dairy, missing := uint(1), uint(2), uint(99)
+	categories := []models.Category{
+		{ID: 2, ParentID: &food, Name: "Dairy", Slug: "dairy"},
+		{ID: 1, Name: "Food", Slug: "food"},
+		{ID: 3, ParentID: &dairy, Name: "Milk", Slug: "milk"},
+		{ID: 4, ParentID: &missing, Name: "Orphan", Slug: "orphan"},
+	}
+
+	tree := buildTree(categories)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "food", tree[0].Slug)
+	assert.Equal(t, "orphan", tree[1].Slug)
+	assert.Len(t, tree[0].Children, 1)
+	assert.Equal(t, "dairy", tree[0].Children[0].Slug)
+	assert.Equal(t, "milk", tree[0].Children[0].Children[0].Slug)
+	assert.Empty(t, tree[1].Children)
+}
+
+func TestCheckMove(t *testing.T) {
+	dairy := &models.Category{ID: 4, Path: "/1/4/"}
+
+	assert.ErrorIs(t, checkMove(dairy, dairy), ErrCategoryCycle)
+	assert.ErrorIs(t, checkMove(dairy, &models.Category{ID: 7, Path: "/1/4/7/"}), ErrCategoryCycle)
+	assert.NoError(t, checkMove(dairy, &models.Category{ID: 1, Path: "/1/"}))
+	assert.NoError(t, checkMove(dairy, &models.Category{ID: 41, Path: "/41/"}))
+}
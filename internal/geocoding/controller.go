@@ -0,0 +1,73 @@
+package geocoding
+
+import (
+	"errors"
+	"net/http"
+	"shop-near-u/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Controller struct {
+	geocoder Geocoder
+}
+
+func NewController(g Geocoder) *Controller {
+	return &Controller{geocoder: g}
+}
+
+func (ctrl *Controller) Geocode(c *gin.Context) {
+	address := c.Query("address")
+	if address == "" {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "address is required")
+		return
+	}
+
+	result, err := ctrl.geocoder.Geocode(c.Request.Context(), address)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			utils.ErrorResponseSimple(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Address resolved successfully", result)
+}
+
+func (ctrl *Controller) ReverseGeocode(c *gin.Context) {
+	lat, err := utils.ParseFloatParam(c.Query("lat"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid latitude")
+		return
+	}
+
+	lon, err := utils.ParseFloatParam(c.Query("lon"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid longitude")
+		return
+	}
+
+	result, err := ctrl.geocoder.ReverseGeocode(c.Request.Context(), lat, lon)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			utils.ErrorResponseSimple(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Coordinates resolved successfully", result)
+}
+
+func RegisterRoutes(r *gin.Engine, geocoder Geocoder) {
+	ctrl := NewController(geocoder)
+
+	geocode := r.Group("/geocode")
+	{
+		geocode.GET("", ctrl.Geocode)
+		geocode.GET("/reverse", ctrl.ReverseGeocode)
+	}
+}
@@ -0,0 +1,81 @@
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrNotFound        = errors.New("location not found")
+	ErrUnknownProvider = errors.New("unknown geocoding provider")
+)
+
+// Result is a resolved place. Address is a display string built by the
+// provider, not necessarily the text that was looked up.
+type Result struct {
+	Address   string  `json:"address"`
+	Postcode  string  `json:"postcode,omitempty"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Geocoder resolves free-text addresses to coordinates and back.
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (*Result, error)
+	ReverseGeocode(ctx context.Context, lat float64, lon float64) (*Result, error)
+}
+
+// Factory builds a provider from its environment configuration.
+type Factory func() (Geocoder, error)
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]Factory{}
+)
+
+// Register makes a provider selectable through GEOCODER_PROVIDER. External
+// providers call it from an init function.
+func Register(name string, factory Factory) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = factory
+}
+
+// Providers lists the registered provider names.
+func Providers() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open builds the named provider.
+func Open(name string) (Geocoder, error) {
+	providersMu.RLock()
+	factory, ok := providers[name]
+	providersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+	}
+	return factory()
+}
+
+// New returns the provider named by GEOCODER_PROVIDER, defaulting to the
+// offline gazetteer.
+func New() (Geocoder, error) {
+	name := os.Getenv("GEOCODER_PROVIDER")
+	if name == "" {
+		name = OfflineProviderName
+	}
+	return Open(name)
+}
@@ -0,0 +1,196 @@
+package geocoding
+
+import (
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const OfflineProviderName = "offline"
+
+// maxReverseDistance is how far, in metres, a point may be from the nearest
+// gazetteer entry and still be labelled with it.
+const maxReverseDistance = 10000.0
+
+// defaultGazetteer is a small sample bundled so the provider works out of the
+// box. Point GEOCODER_DATASET at a full postcode file for real coverage.
+//
+//go:embed data/gazetteer.csv
+var defaultGazetteer string
+
+var postcodePattern = regexp.MustCompile(`\b\d{5,6}\b`)
+
+func init() {
+	Register(OfflineProviderName, func() (Geocoder, error) {
+		if path := os.Getenv("GEOCODER_DATASET"); path != "" {
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+			return NewOfflineGeocoder(file)
+		}
+		return NewOfflineGeocoder(strings.NewReader(defaultGazetteer))
+	})
+}
+
+type gazetteerEntry struct {
+	postcode  string
+	place     string
+	district  string
+	state     string
+	country   string
+	latitude  float64
+	longitude float64
+}
+
+func (e gazetteerEntry) result() *Result {
+	parts := []string{}
+	for _, part := range []string{e.place, e.district, e.state, e.country} {
+		if part != "" && (len(parts) == 0 || parts[len(parts)-1] != part) {
+			parts = append(parts, part)
+		}
+	}
+	address := strings.Join(parts, ", ")
+	if e.postcode != "" {
+		address += " " + e.postcode
+	}
+
+	return &Result{
+		Address:   address,
+		Postcode:  e.postcode,
+		Latitude:  e.latitude,
+		Longitude: e.longitude,
+	}
+}
+
+// OfflineGeocoder answers from an in-memory gazetteer. Lookups match a
+// postcode in the address first, then the most specific place name, and
+// reverse lookups return the nearest entry within maxReverseDistance.
+type OfflineGeocoder struct {
+	entries    []gazetteerEntry
+	byPostcode map[string]int
+}
+
+// NewOfflineGeocoder reads a CSV with the header
+// postcode,place,district,state,country,latitude,longitude.
+func NewOfflineGeocoder(r io.Reader) (*OfflineGeocoder, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 7
+
+	if _, err := reader.Read(); err != nil {
+		return nil, fmt.Errorf("reading gazetteer header: %w", err)
+	}
+
+	geocoder := &OfflineGeocoder{byPostcode: map[string]int{}}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading gazetteer line %d: %w", line, err)
+		}
+
+		lat, err := strconv.ParseFloat(record[5], 64)
+		if err != nil {
+			return nil, fmt.Errorf("gazetteer line %d: invalid latitude: %w", line, err)
+		}
+		lon, err := strconv.ParseFloat(record[6], 64)
+		if err != nil {
+			return nil, fmt.Errorf("gazetteer line %d: invalid longitude: %w", line, err)
+		}
+
+		entry := gazetteerEntry{
+			postcode:  strings.TrimSpace(record[0]),
+			place:     strings.TrimSpace(record[1]),
+			district:  strings.TrimSpace(record[2]),
+			state:     strings.TrimSpace(record[3]),
+			country:   strings.TrimSpace(record[4]),
+			latitude:  lat,
+			longitude: lon,
+		}
+		if entry.postcode != "" {
+			if _, exists := geocoder.byPostcode[entry.postcode]; !exists {
+				geocoder.byPostcode[entry.postcode] = len(geocoder.entries)
+			}
+		}
+		geocoder.entries = append(geocoder.entries, entry)
+	}
+
+	return geocoder, nil
+}
+
+func (g *OfflineGeocoder) Geocode(_ context.Context, address string) (*Result, error) {
+	for _, postcode := range postcodePattern.FindAllString(address, -1) {
+		if idx, ok := g.byPostcode[postcode]; ok {
+			return g.entries[idx].result(), nil
+		}
+	}
+
+	normalized := " " + normalize(address) + " "
+	best, bestScore := -1, 0
+	for i, entry := range g.entries {
+		score := 0
+		if name := normalize(entry.place); name != "" && strings.Contains(normalized, " "+name+" ") {
+			score += 2 * len(name)
+		}
+		if name := normalize(entry.district); name != "" && strings.Contains(normalized, " "+name+" ") {
+			score += len(name)
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	if best < 0 {
+		return nil, ErrNotFound
+	}
+	return g.entries[best].result(), nil
+}
+
+func (g *OfflineGeocoder) ReverseGeocode(_ context.Context, lat float64, lon float64) (*Result, error) {
+	best, bestDistance := -1, math.MaxFloat64
+	for i, entry := range g.entries {
+		distance := haversine(lat, lon, entry.latitude, entry.longitude)
+		if distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+
+	if best < 0 || bestDistance > maxReverseDistance {
+		return nil, ErrNotFound
+	}
+
+	result := g.entries[best].result()
+	result.Latitude = lat
+	result.Longitude = lon
+	return result, nil
+}
+
+// normalize lower-cases the text and collapses punctuation to single spaces.
+func normalize(value string) string {
+	fields := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	return strings.Join(fields, " ")
+}
+
+// haversine returns the great-circle distance in metres.
+func haversine(lat1 float64, lon1 float64, lat2 float64, lon2 float64) float64 {
+	const earthRadius = 6371000.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
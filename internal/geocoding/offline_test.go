@@ -0,0 +1,64 @@
+package geocoding
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGeocoder(t *testing.T) *OfflineGeocoder {
+	t.Helper()
+
+	geocoder, err := NewOfflineGeocoder(strings.NewReader(defaultGazetteer))
+	require.NoError(t, err)
+	return geocoder
+}
+
+func TestOfflineGeocodeByPostcode(t *testing.T) {
+	geocoder := newTestGeocoder(t)
+
+	result, err := geocoder.Geocode(context.Background(), "no 12, 4th street, Chennai - 600020")
+	require.NoError(t, err)
+
+	assert.Equal(t, "600020", result.Postcode)
+	assert.InDelta(t, 13.0012, result.Latitude, 0.0001)
+	assert.InDelta(t, 80.2565, result.Longitude, 0.0001)
+}
+
+func TestOfflineGeocodeByPlaceName(t *testing.T) {
+	geocoder := newTestGeocoder(t)
+
+	result, err := geocoder.Geocode(context.Background(), "2nd Avenue, Anna Nagar, Chennai")
+	require.NoError(t, err)
+	assert.Equal(t, "600040", result.Postcode)
+
+	_, err = geocoder.Geocode(context.Background(), "somewhere unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestOfflineReverseGeocode(t *testing.T) {
+	geocoder := newTestGeocoder(t)
+
+	result, err := geocoder.ReverseGeocode(context.Background(), 13.07439, 80.237617)
+	require.NoError(t, err)
+
+	assert.Contains(t, result.Address, "Chennai")
+	assert.Equal(t, 13.07439, result.Latitude)
+}
+
+func TestOfflineReverseGeocodeTooFar(t *testing.T) {
+	geocoder := newTestGeocoder(t)
+
+	// London is thousands of kilometres from every entry in the sample data.
+	_, err := geocoder.ReverseGeocode(context.Background(), 51.5074, -0.1278)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestOpenUnknownProvider(t *testing.T) {
+	_, err := Open("nope")
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+	assert.Contains(t, Providers(), OfflineProviderName)
+}
@@ -0,0 +1,37 @@
+package media
+
+import (
+	"shop-near-u/internal/models"
+	"time"
+)
+
+type AddGalleryImageDTORequest struct {
+	ImageID  uint   `json:"image_id" binding:"required"`
+	Caption  string `json:"caption" binding:"max=255"`
+	Position *int   `json:"position" binding:"omitempty,min=0"`
+}
+
+type ImageDTOResponse struct {
+	ID          uint   `json:"id"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+
+	Variants  *models.ImageVariantURLs `json:"variants"`
+	CreatedAt time.Time                `json:"created_at"`
+}
+
+type GalleryImageDTOResponse struct {
+	ID       uint   `json:"id"`
+	ImageID  uint   `json:"image_id"`
+	URL      string `json:"url"`
+	Caption  string `json:"caption"`
+	Position int    `json:"position"`
+
+	Variants  *models.ImageVariantURLs `json:"variants"`
+	Width     int                      `json:"width"`
+	Height    int                      `json:"height"`
+	CreatedAt time.Time                `json:"created_at"`
+}
@@ -0,0 +1,240 @@
+package media
+
+import (
+	"errors"
+	"net/http"
+	"shop-near-u/internal/middlewares"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/storage"
+	"shop-near-u/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// multipartOverhead leaves room for the form boundaries and headers around
+// the file itself.
+const multipartOverhead = 1 << 20
+
+// imageCacheControl lets browsers and CDNs keep images for a year. Uploads
+// are content-addressed, so an image ID never changes its bytes.
+const imageCacheControl = "public, max-age=31536000, immutable"
+
+type Controller struct {
+	service *Service
+}
+
+func NewController(s *Service) *Controller {
+	return &Controller{service: s}
+}
+
+// Upload accepts a multipart form with the picture in the "file" field.
+func (ctrl *Controller) Upload(c *gin.Context) {
+	uploader, ok := uploaderFromContext(c)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxImageSize+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.ErrorResponseSimple(c, http.StatusRequestEntityTooLarge, ErrImageTooLarge.Error())
+			return
+		}
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "file is required")
+		return
+	}
+	if header.Size > MaxImageSize {
+		utils.ErrorResponseSimple(c, http.StatusRequestEntityTooLarge, ErrImageTooLarge.Error())
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "failed to read file")
+		return
+	}
+	defer file.Close()
+
+	image, err := ctrl.service.Upload(c.Request.Context(), file, uploader)
+	if err != nil {
+		mediaErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Image uploaded successfully", toImageResponse(*image))
+}
+
+// Serve streams the full-size variant of an image.
+func (ctrl *Controller) Serve(c *gin.Context) {
+	ctrl.serveVariant(c, models.ImageVariantFull)
+}
+
+// ServeVariant streams the variant named in the path: thumb, card or full.
+func (ctrl *Controller) ServeVariant(c *gin.Context) {
+	ctrl.serveVariant(c, c.Param("variant"))
+}
+
+// serveVariant writes the image with long-lived cache headers and answers
+// conditional requests with 304.
+func (ctrl *Controller) serveVariant(c *gin.Context, name string) {
+	imageID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid image ID")
+		return
+	}
+
+	image, err := ctrl.service.GetImage(imageID)
+	if err != nil {
+		mediaErrorResponse(c, err)
+		return
+	}
+
+	key, contentType, err := ctrl.service.ResolveVariant(image, name)
+	if err != nil {
+		mediaErrorResponse(c, err)
+		return
+	}
+
+	etag := `"` + image.Checksum + "-" + name + `"`
+	c.Header("Cache-Control", imageCacheControl)
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	object, err := ctrl.service.Open(c.Request.Context(), key)
+	if err != nil {
+		mediaErrorResponse(c, err)
+		return
+	}
+	defer object.Body.Close()
+
+	c.DataFromReader(http.StatusOK, object.Size, contentType, object.Body, map[string]string{
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (ctrl *Controller) GetGallery(c *gin.Context) {
+	shopID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid shop ID")
+		return
+	}
+
+	images, err := ctrl.service.GetGallery(shopID)
+	if err != nil {
+		mediaErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Gallery retrieved successfully", images)
+}
+
+func (ctrl *Controller) AddGalleryImage(c *gin.Context) {
+	shop, ok := shopFromContext(c)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var dto AddGalleryImageDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	image, err := ctrl.service.AddGalleryImage(shop.ID, &dto)
+	if err != nil {
+		mediaErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Image added to gallery", image)
+}
+
+func (ctrl *Controller) DeleteGalleryImage(c *gin.Context) {
+	shop, ok := shopFromContext(c)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	galleryImageID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid gallery image ID")
+		return
+	}
+
+	if err := ctrl.service.DeleteGalleryImage(galleryImageID, shop.ID); err != nil {
+		mediaErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Image removed from gallery", nil)
+}
+
+func shopFromContext(c *gin.Context) (models.Shop, bool) {
+	shopData, exists := c.Get("shop")
+	if !exists {
+		return models.Shop{}, false
+	}
+	shop, ok := shopData.(models.Shop)
+	return shop, ok
+}
+
+// uploaderFromContext reads the shop set by the shop middlewares or the admin
+// set by RequireAdminAuth.
+func uploaderFromContext(c *gin.Context) (Uploader, bool) {
+	if shop, ok := shopFromContext(c); ok {
+		return Uploader{ShopID: &shop.ID}, true
+	}
+	if userData, exists := c.Get("user"); exists {
+		if user, ok := userData.(models.User); ok {
+			return Uploader{UserID: &user.ID}, true
+		}
+	}
+	return Uploader{}, false
+}
+
+func mediaErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrImageTooLarge):
+		utils.ErrorResponseSimple(c, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, ErrUnsupportedImageType):
+		utils.ErrorResponseSimple(c, http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, ErrInvalidImage):
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrImageNotFound),
+		errors.Is(err, ErrUnknownImageVariant),
+		errors.Is(err, ErrGalleryImageNotFound):
+		utils.ErrorResponseSimple(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrGalleryFull):
+		utils.ErrorResponseSimple(c, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func RegisterRoutes(r *gin.Engine, db *gorm.DB, store storage.Storage) {
+	repo := NewRepository(db)
+	svc := NewService(repo, store)
+	ctrl := NewController(svc)
+
+	r.POST("/api/images", middlewares.RequireShopPermission(db, models.PermissionManageProducts), ctrl.Upload)
+	r.POST("/admin/images", middlewares.RequireAdminAuth(db), ctrl.Upload)
+	r.GET("/api/images/:id", ctrl.Serve)
+	r.GET("/api/images/:id/:variant", ctrl.ServeVariant)
+
+	r.GET("/shops/:id/gallery", ctrl.GetGallery)
+	gallery := r.Group("/shops/gallery")
+	gallery.Use(middlewares.RequireShopOwnerAuth(db))
+	{
+		gallery.POST("", ctrl.AddGalleryImage)
+		gallery.DELETE("/:id", ctrl.DeleteGalleryImage)
+	}
+}
@@ -0,0 +1,82 @@
+package media
+
+import (
+	"shop-near-u/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{DB: db}
+}
+
+func (r *Repository) CreateImage(image *models.Image) error {
+	return r.DB.Create(image).Error
+}
+
+func (r *Repository) GetImage(id uint) (*models.Image, error) {
+	var image models.Image
+	if err := r.DB.First(&image, id).Error; err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *Repository) GetVariant(imageID uint, name string) (*models.ImageVariant, error) {
+	var variant models.ImageVariant
+	if err := r.DB.Where("image_id = ? AND name = ?", imageID, name).First(&variant).Error; err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// FindUploaded returns the uploader's own image with the given checksum.
+func (r *Repository) FindUploaded(checksum string, uploader Uploader) (*models.Image, error) {
+	query := r.DB.Where("checksum = ?", checksum)
+	if uploader.ShopID != nil {
+		query = query.Where("uploaded_by_shop_id = ?", *uploader.ShopID)
+	} else {
+		query = query.Where("uploaded_by_shop_id IS NULL AND uploaded_by_user_id = ?", uploader.UserID)
+	}
+
+	var image models.Image
+	if err := query.First(&image).Error; err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// FindByChecksum returns any image with the given checksum, with its
+// variants, so another uploader's copy can reuse the stored files.
+func (r *Repository) FindByChecksum(checksum string) (*models.Image, error) {
+	var image models.Image
+	if err := r.DB.Preload("Variants").Where("checksum = ?", checksum).Order("id ASC").First(&image).Error; err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *Repository) GetGallery(shopID uint) ([]models.ShopImage, error) {
+	var images []models.ShopImage
+	err := r.DB.Preload("Image").Where("shop_id = ?", shopID).Order("position ASC, id ASC").Find(&images).Error
+	return images, err
+}
+
+func (r *Repository) CountGallery(shopID uint) (int64, error) {
+	var count int64
+	err := r.DB.Model(&models.ShopImage{}).Where("shop_id = ?", shopID).Count(&count).Error
+	return count, err
+}
+
+func (r *Repository) AddGalleryImage(image *models.ShopImage) error {
+	return r.DB.Create(image).Error
+}
+
+func (r *Repository) DeleteGalleryImage(id uint, shopID uint) (bool, error) {
+	result := r.DB.Where("id = ? AND shop_id = ?", id, shopID).Delete(&models.ShopImage{})
+	return result.RowsAffected > 0, result.Error
+}
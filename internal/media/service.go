@@ -0,0 +1,323 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/storage"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxImageSize caps a single upload at 5 MB.
+	MaxImageSize = 5 << 20
+	// MaxImagePixels rejects images too large to decode and resize in memory.
+	MaxImagePixels = 25_000_000
+	// MaxGalleryImages is how many pictures a shop gallery may hold.
+	MaxGalleryImages = 20
+)
+
+// allowedImageTypes maps the accepted content types, detected from the file
+// itself rather than the client's header, to the stored file extension.
+var allowedImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+var (
+	ErrImageTooLarge        = errors.New("image exceeds the 5 MB upload limit")
+	ErrUnsupportedImageType = errors.New("unsupported image type, use JPEG, PNG or GIF")
+	ErrInvalidImage         = errors.New("file is not a readable image")
+	ErrImageNotFound        = errors.New("image not found")
+	ErrUnknownImageVariant  = errors.New("unknown image variant, use thumb, card or full")
+	ErrGalleryFull          = errors.New("shop gallery is full")
+	ErrGalleryImageNotFound = errors.New("gallery image not found")
+)
+
+// Uploader identifies who sent an image: a shop (owner or staff) or an admin.
+type Uploader struct {
+	ShopID *uint
+	UserID *uint
+}
+
+type Service struct {
+	repository *Repository
+	store      storage.Storage
+}
+
+func NewService(r *Repository, store storage.Storage) *Service {
+	return &Service{repository: r, store: store}
+}
+
+// Upload validates an image and stores its thumb, card and full variants.
+// Uploading the same file again returns the uploader's existing record; a
+// file someone else uploaded first gets a new record pointing at the files
+// already stored.
+func (s *Service) Upload(ctx context.Context, file io.Reader, uploader Uploader) (*models.Image, error) {
+	data, err := io.ReadAll(io.LimitReader(file, MaxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxImageSize {
+		return nil, ErrImageTooLarge
+	}
+
+	contentType, _, _, err := inspectImage(data)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	existing, err := s.repository.FindUploaded(checksum, uploader)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	shared, err := s.repository.FindByChecksum(checksum)
+	if err == nil {
+		record := copyImage(shared, uploader)
+		if err := s.repository.CreateImage(record); err != nil {
+			return nil, err
+		}
+		return record, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	variants, err := buildVariants(data, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.Image{
+		Checksum:         checksum,
+		UploadedByShopID: uploader.ShopID,
+		UploadedByUserID: uploader.UserID,
+	}
+	for _, variant := range variants {
+		key := "images/" + checksum[:2] + "/" + checksum + "/" + variant.Name + allowedImageTypes[variant.ContentType]
+		if err := s.store.Put(ctx, key, bytes.NewReader(variant.Data), int64(len(variant.Data)), variant.ContentType); err != nil {
+			return nil, err
+		}
+
+		record.Variants = append(record.Variants, models.ImageVariant{
+			Name:        variant.Name,
+			Key:         key,
+			ContentType: variant.ContentType,
+			Size:        int64(len(variant.Data)),
+			Width:       variant.Width,
+			Height:      variant.Height,
+		})
+		if variant.Name == models.ImageVariantFull {
+			record.Key = key
+			record.ContentType = variant.ContentType
+			record.Size = int64(len(variant.Data))
+			record.Width = variant.Width
+			record.Height = variant.Height
+		}
+	}
+
+	if err := s.repository.CreateImage(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// copyImage makes a record for uploader that shares the stored files of an
+// image someone else uploaded.
+func copyImage(shared *models.Image, uploader Uploader) *models.Image {
+	record := &models.Image{
+		Key:              shared.Key,
+		ContentType:      shared.ContentType,
+		Size:             shared.Size,
+		Width:            shared.Width,
+		Height:           shared.Height,
+		Checksum:         shared.Checksum,
+		UploadedByShopID: uploader.ShopID,
+		UploadedByUserID: uploader.UserID,
+	}
+	for _, variant := range shared.Variants {
+		record.Variants = append(record.Variants, models.ImageVariant{
+			Name:        variant.Name,
+			Key:         variant.Key,
+			ContentType: variant.ContentType,
+			Size:        variant.Size,
+			Width:       variant.Width,
+			Height:      variant.Height,
+		})
+	}
+	return record
+}
+
+// inspectImage sniffs the content type from the bytes and reads the
+// dimensions, rejecting anything that is not a decodable JPEG, PNG or GIF.
+func inspectImage(data []byte) (string, int, int, error) {
+	contentType := http.DetectContentType(data)
+	if _, ok := allowedImageTypes[contentType]; !ok {
+		return "", 0, 0, ErrUnsupportedImageType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return "", 0, 0, ErrInvalidImage
+	}
+	if config.Width*config.Height > MaxImagePixels {
+		return "", 0, 0, ErrImageTooLarge
+	}
+	return contentType, config.Width, config.Height, nil
+}
+
+func (s *Service) GetImage(id uint) (*models.Image, error) {
+	record, err := s.repository.GetImage(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImageNotFound
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+// ResolveVariant returns the storage key and content type of the named
+// variant. Images uploaded before variants existed serve their single stored
+// file for every variant.
+func (s *Service) ResolveVariant(record *models.Image, name string) (string, string, error) {
+	known := false
+	for _, size := range models.ImageVariantSizes {
+		if size.Name == name {
+			known = true
+		}
+	}
+	if !known {
+		return "", "", ErrUnknownImageVariant
+	}
+
+	variant, err := s.repository.GetVariant(record.ID, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return record.Key, record.ContentType, nil
+		}
+		return "", "", err
+	}
+	return variant.Key, variant.ContentType, nil
+}
+
+// Open returns the stored bytes under key. The caller closes the body.
+func (s *Service) Open(ctx context.Context, key string) (*storage.Object, error) {
+	object, err := s.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrImageNotFound
+		}
+		return nil, err
+	}
+	return object, nil
+}
+
+func (s *Service) GetGallery(shopID uint) ([]GalleryImageDTOResponse, error) {
+	images, err := s.repository.GetGallery(shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]GalleryImageDTOResponse, 0, len(images))
+	for _, image := range images {
+		response = append(response, toGalleryImageResponse(image))
+	}
+	return response, nil
+}
+
+// AddGalleryImage puts one of the shop's own uploads into its gallery.
+// Without a position the image goes to the end.
+func (s *Service) AddGalleryImage(shopID uint, dto *AddGalleryImageDTORequest) (*GalleryImageDTOResponse, error) {
+	record, err := s.GetImage(dto.ImageID)
+	if err != nil {
+		return nil, err
+	}
+	if record.UploadedByShopID == nil || *record.UploadedByShopID != shopID {
+		return nil, ErrImageNotFound
+	}
+
+	count, err := s.repository.CountGallery(shopID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxGalleryImages {
+		return nil, ErrGalleryFull
+	}
+
+	position := int(count)
+	if dto.Position != nil {
+		position = *dto.Position
+	}
+
+	galleryImage := &models.ShopImage{
+		ShopID:   shopID,
+		ImageID:  record.ID,
+		Caption:  dto.Caption,
+		Position: position,
+	}
+	if err := s.repository.AddGalleryImage(galleryImage); err != nil {
+		return nil, err
+	}
+	galleryImage.Image = *record
+	galleryImage.Images = models.NewImageVariantURLs(record.ID)
+
+	response := toGalleryImageResponse(*galleryImage)
+	return &response, nil
+}
+
+func (s *Service) DeleteGalleryImage(id uint, shopID uint) error {
+	deleted, err := s.repository.DeleteGalleryImage(id, shopID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrGalleryImageNotFound
+	}
+	return nil
+}
+
+func toImageResponse(image models.Image) ImageDTOResponse {
+	return ImageDTOResponse{
+		ID:          image.ID,
+		URL:         models.ImageURL(image.ID),
+		ContentType: image.ContentType,
+		Size:        image.Size,
+		Width:       image.Width,
+		Height:      image.Height,
+		Variants:    models.NewImageVariantURLs(image.ID),
+		CreatedAt:   image.CreatedAt,
+	}
+}
+
+func toGalleryImageResponse(image models.ShopImage) GalleryImageDTOResponse {
+	return GalleryImageDTOResponse{
+		ID:        image.ID,
+		ImageID:   image.ImageID,
+		URL:       models.ImageURL(image.ImageID),
+		Caption:   image.Caption,
+		Position:  image.Position,
+		Variants:  image.Images,
+		Width:     image.Image.Width,
+		Height:    image.Image.Height,
+		CreatedAt: image.CreatedAt,
+	}
+}
@@ -0,0 +1,62 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"shop-near-u/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestInspectImage(t *testing.T) {
+	contentType, width, height, err := inspectImage(encodePNG(t, 40, 30))
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, 40, width)
+	assert.Equal(t, 30, height)
+
+	_, _, _, err = inspectImage([]byte("<html><body>not an image</body></html>"))
+	assert.ErrorIs(t, err, ErrUnsupportedImageType)
+
+	truncated := encodePNG(t, 40, 30)[:12]
+	_, _, _, err = inspectImage(truncated)
+	assert.ErrorIs(t, err, ErrInvalidImage)
+}
+
+func TestCopyImage(t *testing.T) {
+	first, second := uint(1), uint(2)
+	shared := &models.Image{
+		ID:               7,
+		Key:              "images/ab/abc/full.jpg",
+		ContentType:      "image/jpeg",
+		Checksum:         "abc",
+		UploadedByShopID: &first,
+		Variants: []models.ImageVariant{
+			{ID: 3, ImageID: 7, Name: models.ImageVariantThumb, Key: "images/ab/abc/thumb.jpg"},
+		},
+	}
+
+	record := copyImage(shared, Uploader{ShopID: &second})
+	assert.Zero(t, record.ID)
+	assert.Equal(t, shared.Key, record.Key)
+	assert.Equal(t, &second, record.UploadedByShopID)
+	require.Len(t, record.Variants, 1)
+	assert.Zero(t, record.Variants[0].ID)
+	assert.Zero(t, record.Variants[0].ImageID)
+	assert.Equal(t, "images/ab/abc/thumb.jpg", record.Variants[0].Key)
+}
@@ -0,0 +1,227 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"shop-near-u/internal/models"
+)
+
+const variantJPEGQuality = 82
+
+// encodedVariant is one resized copy ready to be stored.
+type encodedVariant struct {
+	Name        string
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// buildVariants decodes an upload, turns it upright according to its EXIF
+// orientation and re-encodes it at each size in models.ImageVariantSizes.
+// Re-encoding writes no metadata, so EXIF blocks, including GPS location,
+// never reach storage. Images with transparency are kept as PNG, everything
+// else becomes JPEG. Animated GIFs keep only their first frame.
+func buildVariants(data []byte, contentType string) ([]encodedVariant, error) {
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	src := toRGBA(decoded)
+	if contentType == "image/jpeg" {
+		src = applyOrientation(src, exifOrientation(data))
+	}
+	opaque := src.Opaque()
+
+	bounds := src.Bounds()
+	variants := make([]encodedVariant, 0, len(models.ImageVariantSizes))
+	for _, size := range models.ImageVariantSizes {
+		width, height := fitWithin(bounds.Dx(), bounds.Dy(), size.MaxEdge)
+		resized := resizeBox(src, width, height)
+
+		var buf bytes.Buffer
+		variant := encodedVariant{Name: size.Name, Width: width, Height: height}
+		if opaque {
+			variant.ContentType = "image/jpeg"
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: variantJPEGQuality})
+		} else {
+			variant.ContentType = "image/png"
+			err = png.Encode(&buf, resized)
+		}
+		if err != nil {
+			return nil, err
+		}
+		variant.Data = buf.Bytes()
+		variants = append(variants, variant)
+	}
+	return variants, nil
+}
+
+// fitWithin scales width and height down so the longer edge is at most
+// maxEdge, keeping the aspect ratio. Images already small enough keep their
+// size.
+func fitWithin(width, height, maxEdge int) (int, int) {
+	if width <= maxEdge && height <= maxEdge {
+		return width, height
+	}
+	if width >= height {
+		return maxEdge, max(1, height*maxEdge/width)
+	}
+	return max(1, width*maxEdge/height), maxEdge
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// resizeBox shrinks src to width x height by averaging the block of source
+// pixels behind each destination pixel. It works on premultiplied colour so
+// transparent edges do not darken.
+func resizeBox(src *image.RGBA, width, height int) *image.RGBA {
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcH / height
+		y1 := max((y+1)*srcH/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := x * srcW / width
+			x1 := max((x+1)*srcW/width, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+
+			d := dst.Pix[y*dst.Stride+x*4 : y*dst.Stride+x*4+4]
+			d[0] = uint8((r + n/2) / n)
+			d[1] = uint8((g + n/2) / n)
+			d[2] = uint8((b + n/2) / n)
+			d[3] = uint8((a + n/2) / n)
+		}
+	}
+	return dst
+}
+
+// applyOrientation rotates or flips src so that an image tagged with EXIF
+// orientation 2-8 displays upright once the tag is gone.
+func applyOrientation(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				sx, sy = w-1-x, y
+			case 3: // rotated 180
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				sx, sy = x, h-1-y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // needs 90 clockwise
+				sx, sy = y, h-1-x
+			case 7: // transversed
+				sx, sy = w-1-y, h-1-x
+			case 8: // needs 90 anticlockwise
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[y*dst.Stride+x*4:y*dst.Stride+x*4+4], src.Pix[sy*src.Stride+sx*4:sy*src.Stride+sx*4+4])
+		}
+	}
+	return dst
+}
+
+// exifOrientation reads the orientation tag from a JPEG's EXIF block. It
+// returns 1, upright, when there is no tag or the block cannot be read.
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xDA || marker == 0xD9 { // start of scan or end of image
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		if length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if orientation >= 1 && orientation <= 8 {
+				return orientation
+			}
+			return 1
+		}
+	}
+	return 1
+}
@@ -0,0 +1,132 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"shop-near-u/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exifSegment builds an APP1 block holding an orientation tag and a pointer
+// to a GPS IFD, as phone cameras write them.
+func exifSegment(orientation uint16) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("MM\x00\x2a")
+	binary.Write(&tiff, binary.BigEndian, uint32(8))
+	binary.Write(&tiff, binary.BigEndian, uint16(2))
+	// Orientation, SHORT, count 1
+	binary.Write(&tiff, binary.BigEndian, []uint16{0x0112, 3})
+	binary.Write(&tiff, binary.BigEndian, uint32(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{orientation, 0})
+	// GPS IFD pointer, LONG, count 1
+	binary.Write(&tiff, binary.BigEndian, []uint16{0x8825, 4})
+	binary.Write(&tiff, binary.BigEndian, []uint32{1, 38})
+	binary.Write(&tiff, binary.BigEndian, uint32(0))
+	tiff.WriteString("GPS-LATITUDE-12.9716N")
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// taggedJPEG encodes a width x height photo and inserts an EXIF block
+// straight after the start-of-image marker.
+func taggedJPEG(t *testing.T, width, height int, orientation uint16) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	encoded := buf.Bytes()
+
+	tagged := append([]byte{}, encoded[:2]...)
+	tagged = append(tagged, exifSegment(orientation)...)
+	return append(tagged, encoded[2:]...)
+}
+
+func TestExifOrientation(t *testing.T) {
+	assert.Equal(t, 6, exifOrientation(taggedJPEG(t, 20, 10, 6)))
+	assert.Equal(t, 1, exifOrientation(encodePNG(t, 4, 4)))
+	assert.Equal(t, 1, exifOrientation([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF}))
+}
+
+func TestBuildVariantsStripsExifAndRotates(t *testing.T) {
+	data := taggedJPEG(t, 400, 200, 6)
+
+	variants, err := buildVariants(data, "image/jpeg")
+	require.NoError(t, err)
+	require.Len(t, variants, len(models.ImageVariantSizes))
+
+	for _, variant := range variants {
+		assert.Equal(t, "image/jpeg", variant.ContentType)
+		assert.NotContains(t, string(variant.Data), "Exif")
+		assert.NotContains(t, string(variant.Data), "GPS-LATITUDE")
+		assert.Equal(t, 1, exifOrientation(variant.Data))
+
+		config, err := jpeg.DecodeConfig(bytes.NewReader(variant.Data))
+		require.NoError(t, err)
+		assert.Equal(t, variant.Width, config.Width)
+		assert.Equal(t, variant.Height, config.Height)
+	}
+
+	// Orientation 6 turns the 400x200 landscape into a 200x400 portrait
+	assert.Equal(t, models.ImageVariantThumb, variants[0].Name)
+	assert.Equal(t, 80, variants[0].Width)
+	assert.Equal(t, 160, variants[0].Height)
+	assert.Equal(t, 200, variants[2].Width)
+	assert.Equal(t, 400, variants[2].Height)
+}
+
+func TestBuildVariantsKeepsTransparencyAsPNG(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	img.Set(5, 5, color.NRGBA{R: 255, A: 128})
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	variants, err := buildVariants(buf.Bytes(), "image/png")
+	require.NoError(t, err)
+	for _, variant := range variants {
+		assert.Equal(t, "image/png", variant.ContentType)
+	}
+}
+
+func TestFitWithin(t *testing.T) {
+	w, h := fitWithin(4000, 3000, 480)
+	assert.Equal(t, 480, w)
+	assert.Equal(t, 360, h)
+
+	w, h = fitWithin(300, 3000, 160)
+	assert.Equal(t, 16, w)
+	assert.Equal(t, 160, h)
+
+	w, h = fitWithin(100, 50, 1600)
+	assert.Equal(t, 100, w)
+	assert.Equal(t, 50, h)
+}
+
+func TestApplyOrientation(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	src.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	// The top-left pixel ends up top-right after a clockwise turn
+	rotated := applyOrientation(src, 6)
+	assert.Equal(t, image.Rect(0, 0, 2, 3), rotated.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, rotated.RGBAAt(1, 0))
+
+	assert.Same(t, src, applyOrientation(src, 1))
+}
@@ -0,0 +1,80 @@
+package merchant
+
+type MerchantRegisterDTORequest struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type MerchantLoginDTORequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// CreateBranchDTORequest opens a new shop under the merchant. Branches have no
+// password of their own; they are reached by selecting them after a merchant
+// login. Latitude and Longitude must be sent together or both omitted, in
+// which case they are geocoded from Address.
+type CreateBranchDTORequest struct {
+	Name   string `json:"name" binding:"required"`
+	Type   string `json:"type" binding:"required"`
+	Email  string `json:"email" binding:"required,email"`
+	Mobile string `json:"mobile" binding:"required"`
+
+	Address   string   `json:"address" binding:"required"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,gte=-180,lte=180"`
+
+	SupportsDelivery bool    `json:"supports_delivery"`
+	DeliveryRadius   float64 `json:"delivery_radius" binding:"gte=0"`
+}
+
+// LinkShopDTORequest moves an existing shop account under the merchant, using
+// that shop's own login to prove ownership.
+type LinkShopDTORequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// PriceListItemDTO sets the price of one catalog product. Stock and
+// IsAvailable are left as they are on branches that already stock the
+// product unless given; new listings default to no stock and available.
+type PriceListItemDTO struct {
+	CatalogID   uint    `json:"catalog_id" binding:"required"`
+	Price       float64 `json:"price" binding:"required,gt=0"`
+	Discount    float64 `json:"discount" binding:"gte=0,lte=100"`
+	Stock       *int    `json:"stock" binding:"omitempty,gte=0"`
+	IsAvailable *bool   `json:"is_available"`
+}
+
+// PushPriceListDTORequest applies the items to the listed branches, or to
+// every open, unsuspended branch when ShopIDs is empty.
+type PushPriceListDTORequest struct {
+	ShopIDs []uint             `json:"shop_ids"`
+	Items   []PriceListItemDTO `json:"items" binding:"required,min=1,dive"`
+}
+
+type PriceListResultDTOResponse struct {
+	ShopID  uint `json:"shop_id"`
+	Created int  `json:"created"`
+	Updated int  `json:"updated"`
+}
+
+type MerchantShopDTOResponse struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Address  string `json:"address"`
+	Selected bool   `json:"selected"`
+
+	ClosedPermanently bool `json:"closed_permanently"`
+}
+
+type MerchantSessionDTOResponse struct {
+	ID             uint                      `json:"id"`
+	Name           string                    `json:"name"`
+	Email          string                    `json:"email"`
+	SelectedShopID uint                      `json:"selected_shop_id,omitempty"`
+	Shops          []MerchantShopDTOResponse `json:"shops"`
+	Token          string                    `json:"token,omitempty"`
+}
@@ -0,0 +1,316 @@
+package merchant
+
+import (
+	"errors"
+	"net/http"
+	"shop-near-u/internal/geocoding"
+	"shop-near-u/internal/middlewares"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Controller struct {
+	service *Service
+}
+
+func NewController(s *Service) *Controller {
+	return &Controller{service: s}
+}
+
+func (ctrl *Controller) Register(c *gin.Context) {
+	var dto MerchantRegisterDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	merchant, err := ctrl.service.Register(&dto)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	token, err := startSession(c, merchant, nil)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, "failed to generate token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Merchant registered successfully", toSessionResponse(merchant, nil, nil, token))
+}
+
+func (ctrl *Controller) Login(c *gin.Context) {
+	var dto MerchantLoginDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	merchant, shop, shops, err := ctrl.service.Authenticate(&dto)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			utils.ErrorResponseSimple(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	token, err := startSession(c, merchant, shop)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, "failed to generate token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Merchant logged in successfully", toSessionResponse(merchant, shop, shops, token))
+}
+
+func (ctrl *Controller) Logout(c *gin.Context) {
+	utils.SetCookie("", -1, c)
+
+	utils.SuccessResponse(c, http.StatusOK, "Successfully logged out", nil)
+}
+
+func (ctrl *Controller) GetShops(c *gin.Context) {
+	merchant, ok := merchantFromContext(c)
+	if !ok {
+		return
+	}
+
+	shops, err := ctrl.service.GetShops(merchant.ID)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var selected *models.Shop
+	if shopID := selectedShopID(c); shopID != 0 {
+		for i := range shops {
+			if shops[i].ID == shopID {
+				selected = &shops[i]
+			}
+		}
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Merchant shops retrieved successfully", toSessionResponse(&merchant, selected, shops, ""))
+}
+
+// SelectShop switches the session to another branch. Shop owner endpoints act
+// on the selected branch from then on.
+func (ctrl *Controller) SelectShop(c *gin.Context) {
+	merchant, ok := merchantFromContext(c)
+	if !ok {
+		return
+	}
+
+	shopID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid shop ID")
+		return
+	}
+
+	shop, err := ctrl.service.SelectShop(merchant.ID, shopID)
+	if err != nil {
+		merchantErrorResponse(c, err)
+		return
+	}
+
+	shops, err := ctrl.service.GetShops(merchant.ID)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	token, err := startSession(c, &merchant, shop)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, "failed to generate token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shop selected successfully", toSessionResponse(&merchant, shop, shops, token))
+}
+
+func (ctrl *Controller) CreateBranch(c *gin.Context) {
+	var dto CreateBranchDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	merchant, ok := merchantFromContext(c)
+	if !ok {
+		return
+	}
+
+	shop, err := ctrl.service.CreateBranch(c.Request.Context(), &merchant, &dto)
+	if err != nil {
+		merchantErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Branch created successfully", toShopResponse(*shop, 0))
+}
+
+func (ctrl *Controller) LinkShop(c *gin.Context) {
+	var dto LinkShopDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	merchant, ok := merchantFromContext(c)
+	if !ok {
+		return
+	}
+
+	shop, err := ctrl.service.LinkShop(merchant.ID, &dto)
+	if err != nil {
+		merchantErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shop linked successfully", toShopResponse(*shop, 0))
+}
+
+func (ctrl *Controller) PushPriceList(c *gin.Context) {
+	var dto PushPriceListDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	merchant, ok := merchantFromContext(c)
+	if !ok {
+		return
+	}
+
+	results, err := ctrl.service.PushPriceList(merchant.ID, &dto)
+	if err != nil {
+		merchantErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Price list applied successfully", results)
+}
+
+// startSession sets the auth cookie. With a shop selected the cookie is a
+// shop owner token for that branch; otherwise it is a bare merchant token.
+func startSession(c *gin.Context, merchant *models.Merchant, shop *models.Shop) (string, error) {
+	var token string
+	var err error
+	if shop != nil {
+		token, err = utils.GenerateShopAccessToken(shop.ID, merchant.ID)
+	} else {
+		token, err = utils.GenerateAccessToken(merchant.ID, models.RoleMerchant)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	utils.SetCookie(token, 3600*24*30, c)
+	return token, nil
+}
+
+// selectedShopID returns the branch the current session is on, if any.
+func selectedShopID(c *gin.Context) uint {
+	tokenString, err := c.Cookie("Authorization")
+	if err != nil {
+		return 0
+	}
+
+	subject, role, err := utils.ParseToken(tokenString)
+	if err != nil || role != models.RoleShopOwner {
+		return 0
+	}
+	return uint(subject)
+}
+
+func merchantFromContext(c *gin.Context) (models.Merchant, bool) {
+	merchantInterface, exists := c.Get("merchant")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return models.Merchant{}, false
+	}
+
+	merchant, ok := merchantInterface.(models.Merchant)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, "failed to parse merchant data")
+		return models.Merchant{}, false
+	}
+	return merchant, true
+}
+
+func toShopResponse(shop models.Shop, selectedID uint) MerchantShopDTOResponse {
+	return MerchantShopDTOResponse{
+		ID:       shop.ID,
+		Name:     shop.Name,
+		Type:     shop.Type,
+		Address:  shop.Address,
+		Selected: selectedID != 0 && shop.ID == selectedID,
+
+		ClosedPermanently: shop.ClosedAt != nil,
+	}
+}
+
+func toSessionResponse(merchant *models.Merchant, selected *models.Shop, shops []models.Shop, token string) MerchantSessionDTOResponse {
+	response := MerchantSessionDTOResponse{
+		ID:    merchant.ID,
+		Name:  merchant.Name,
+		Email: merchant.Email,
+		Shops: make([]MerchantShopDTOResponse, 0, len(shops)),
+		Token: token,
+	}
+	if selected != nil {
+		response.SelectedShopID = selected.ID
+	}
+	for _, shop := range shops {
+		response.Shops = append(response.Shops, toShopResponse(shop, response.SelectedShopID))
+	}
+	return response
+}
+
+func merchantErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidCredentials):
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, ErrShopNotFound):
+		utils.ErrorResponseSimple(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrShopClosed):
+		utils.ErrorResponseSimple(c, http.StatusGone, err.Error())
+	case errors.Is(err, ErrShopSuspended):
+		utils.ErrorResponseSimple(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, ErrShopAlreadyLinked):
+		utils.ErrorResponseSimple(c, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrAddressNotGeocoded):
+		utils.ErrorResponseSimple(c, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, ErrNoOpenShops),
+		errors.Is(err, ErrUnknownCatalogProduct),
+		errors.Is(err, ErrDuplicateCatalogProduct),
+		errors.Is(err, ErrDeliveryRadiusRequired),
+		errors.Is(err, ErrIncompleteCoordinates):
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func RegisterRoutes(r *gin.Engine, db *gorm.DB, geocoder geocoding.Geocoder) {
+	repo := NewRepository(db)
+	svc := NewService(repo, geocoder)
+	ctrl := NewController(svc)
+
+	merchants := r.Group("/merchants")
+	{
+		merchants.POST("/register", ctrl.Register)
+		merchants.POST("/login", ctrl.Login)
+		merchants.POST("/logout", middlewares.RequireMerchantAuth(db), ctrl.Logout)
+		merchants.GET("/shops", middlewares.RequireMerchantAuth(db), ctrl.GetShops)
+		merchants.POST("/shops", middlewares.RequireMerchantAuth(db), ctrl.CreateBranch)
+		merchants.POST("/shops/link", middlewares.RequireMerchantAuth(db), ctrl.LinkShop)
+		merchants.POST("/shops/:id/select", middlewares.RequireMerchantAuth(db), ctrl.SelectShop)
+		merchants.PUT("/price-list", middlewares.RequireMerchantAuth(db), ctrl.PushPriceList)
+	}
+}
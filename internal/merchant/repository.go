@@ -0,0 +1,191 @@
+package merchant
+
+import (
+	"fmt"
+	"shop-near-u/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{DB: db}
+}
+
+func (r *Repository) CreateMerchant(merchant *models.Merchant) error {
+	return r.DB.Create(merchant).Error
+}
+
+func (r *Repository) FindByEmail(email string) (*models.Merchant, error) {
+	var merchant models.Merchant
+	if err := r.DB.Where("email = ?", email).First(&merchant).Error; err != nil {
+		return nil, err
+	}
+	return &merchant, nil
+}
+
+func (r *Repository) GetShops(merchantID uint) ([]models.Shop, error) {
+	var shops []models.Shop
+	err := r.DB.Where("merchant_id = ?", merchantID).Order("id ASC").Find(&shops).Error
+	return shops, err
+}
+
+func (r *Repository) GetShop(merchantID uint, shopID uint) (*models.Shop, error) {
+	var shop models.Shop
+	if err := r.DB.Where("id = ? AND merchant_id = ?", shopID, merchantID).First(&shop).Error; err != nil {
+		return nil, err
+	}
+	return &shop, nil
+}
+
+func (r *Repository) FindShopByEmail(email string) (*models.Shop, error) {
+	var shop models.Shop
+	if err := r.DB.Where("email = ?", email).First(&shop).Error; err != nil {
+		return nil, err
+	}
+	return &shop, nil
+}
+
+func (r *Repository) FindCategoryID(shopType string) (*uint, error) {
+	return models.ShopTypeCategoryID(r.DB, shopType)
+}
+
+func (r *Repository) CreateShop(shop *models.Shop) error {
+	return r.DB.Create(shop).Error
+}
+
+// LinkShop attaches a standalone shop to the merchant. It fails when the shop
+// already belongs to a merchant.
+func (r *Repository) LinkShop(shopID uint, merchantID uint) error {
+	result := r.DB.Model(&models.Shop{}).
+		Where("id = ? AND merchant_id IS NULL", shopID).
+		Update("merchant_id", merchantID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrShopAlreadyLinked
+	}
+	return nil
+}
+
+// ApplyPriceList upserts the items into every listed shop in one transaction
+// and writes each created or updated listing to the shop's inventory log.
+func (r *Repository) ApplyPriceList(shopIDs []uint, items []PriceListItemDTO) ([]PriceListResultDTOResponse, error) {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	catalogIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		catalogIDs = append(catalogIDs, item.CatalogID)
+	}
+
+	var found int64
+	if err := tx.Model(&models.CatalogProduct{}).Where("id IN ? AND status = ? AND retired_at IS NULL", catalogIDs, models.CatalogStatusApproved).Count(&found).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if int(found) != len(catalogIDs) {
+		tx.Rollback()
+		return nil, ErrUnknownCatalogProduct
+	}
+
+	results := make([]PriceListResultDTOResponse, 0, len(shopIDs))
+	var changes []models.InventoryChange
+	for _, shopID := range shopIDs {
+		result := PriceListResultDTOResponse{ShopID: shopID}
+
+		for _, item := range items {
+			var existing models.ShopProduct
+			if err := tx.Where("shop_id = ? AND catalog_id = ?", shopID, item.CatalogID).Limit(1).Find(&existing).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+
+			if existing.ID != 0 {
+				updates := map[string]interface{}{
+					"price":    item.Price,
+					"discount": item.Discount,
+				}
+				if item.Stock != nil {
+					updates["stock"] = *item.Stock
+				}
+				if item.IsAvailable != nil {
+					updates["is_available"] = *item.IsAvailable
+				}
+				change := models.InventoryChange{
+					ShopID:        shopID,
+					ShopProductID: existing.ID,
+					Action:        models.InventoryActionUpdated,
+					StockBefore:   existing.Stock,
+					StockAfter:    existing.Stock,
+					PriceBefore:   existing.Price,
+					PriceAfter:    item.Price,
+				}
+				if item.Stock != nil {
+					change.StockAfter = *item.Stock
+				}
+				if err := tx.Model(&existing).Updates(updates).Error; err != nil {
+					tx.Rollback()
+					return nil, err
+				}
+				changes = append(changes, change)
+				result.Updated++
+				continue
+			}
+
+			product := models.ShopProduct{
+				ShopID:      shopID,
+				CatalogID:   item.CatalogID,
+				Price:       item.Price,
+				Discount:    item.Discount,
+				IsAvailable: true,
+			}
+			if item.Stock != nil {
+				product.Stock = *item.Stock
+			}
+			if item.IsAvailable != nil {
+				product.IsAvailable = *item.IsAvailable
+			}
+			// Select every column so an explicit is_available=false is not
+			// replaced by the column default.
+			if err := tx.Select("*").Omit("ID").Create(&product).Error; err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("failed to list catalog product %d in shop %d: %w", item.CatalogID, shopID, err)
+			}
+			changes = append(changes, models.InventoryChange{
+				ShopID:        shopID,
+				ShopProductID: product.ID,
+				Action:        models.InventoryActionCreated,
+				StockAfter:    product.Stock,
+				PriceAfter:    product.Price,
+			})
+			result.Created++
+		}
+
+		results = append(results, result)
+	}
+
+	if len(changes) > 0 {
+		if err := tx.Create(&changes).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to log inventory changes: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
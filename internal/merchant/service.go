@@ -0,0 +1,236 @@
+package merchant
+
+import (
+	"context"
+	"errors"
+	"shop-near-u/internal/geocoding"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/utils"
+
+	"github.com/restayway/gogis"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCredentials      = errors.New("invalid credentials")
+	ErrShopNotFound            = errors.New("shop not found for this merchant")
+	ErrShopClosed              = errors.New("shop closed permanently")
+	ErrShopSuspended           = errors.New("shop suspended")
+	ErrShopAlreadyLinked       = errors.New("shop already belongs to a merchant")
+	ErrNoOpenShops             = errors.New("merchant has no open shops")
+	ErrUnknownCatalogProduct   = errors.New("price list references an unknown or unapproved catalog product")
+	ErrDuplicateCatalogProduct = errors.New("price list lists the same catalog product more than once")
+	ErrDeliveryRadiusRequired  = errors.New("delivery_radius must be greater than 0 when delivery is supported")
+	ErrAddressNotGeocoded      = errors.New("could not find coordinates for the address, please provide latitude and longitude")
+	ErrIncompleteCoordinates   = errors.New("latitude and longitude must be provided together")
+)
+
+type Service struct {
+	repository *Repository
+	geocoder   geocoding.Geocoder
+}
+
+func NewService(r *Repository, g geocoding.Geocoder) *Service {
+	return &Service{repository: r, geocoder: g}
+}
+
+func (s *Service) Register(dto *MerchantRegisterDTORequest) (*models.Merchant, error) {
+	password, err := utils.HashPassword(dto.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	merchant := &models.Merchant{
+		Name:     dto.Name,
+		Email:    dto.Email,
+		Password: password,
+	}
+	if err := s.repository.CreateMerchant(merchant); err != nil {
+		return nil, err
+	}
+	return merchant, nil
+}
+
+// Authenticate checks the merchant's credentials and picks the shop the
+// session starts on, which is nil when the merchant has no open shop yet.
+func (s *Service) Authenticate(dto *MerchantLoginDTORequest) (*models.Merchant, *models.Shop, []models.Shop, error) {
+	merchant, err := s.repository.FindByEmail(dto.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, ErrInvalidCredentials
+		}
+		return nil, nil, nil, err
+	}
+
+	if err := utils.CheckPasswordHash(dto.Password, merchant.Password); err != nil {
+		return nil, nil, nil, ErrInvalidCredentials
+	}
+
+	shops, err := s.repository.GetShops(merchant.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return merchant, defaultShop(shops), shops, nil
+}
+
+func (s *Service) GetShops(merchantID uint) ([]models.Shop, error) {
+	return s.repository.GetShops(merchantID)
+}
+
+// SelectShop checks that the shop is an open branch of the merchant.
+func (s *Service) SelectShop(merchantID uint, shopID uint) (*models.Shop, error) {
+	shop, err := s.repository.GetShop(merchantID, shopID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShopNotFound
+		}
+		return nil, err
+	}
+	if shop.ClosedAt != nil {
+		return nil, ErrShopClosed
+	}
+	if shop.SuspendedAt != nil {
+		return nil, ErrShopSuspended
+	}
+	return shop, nil
+}
+
+func (s *Service) CreateBranch(ctx context.Context, merchant *models.Merchant, dto *CreateBranchDTORequest) (*models.Shop, error) {
+	if dto.SupportsDelivery && dto.DeliveryRadius <= 0 {
+		return nil, ErrDeliveryRadiusRequired
+	}
+
+	if (dto.Latitude == nil) != (dto.Longitude == nil) {
+		return nil, ErrIncompleteCoordinates
+	}
+
+	if dto.Latitude == nil {
+		result, err := s.geocoder.Geocode(ctx, dto.Address)
+		if err != nil {
+			if errors.Is(err, geocoding.ErrNotFound) {
+				return nil, ErrAddressNotGeocoded
+			}
+			return nil, err
+		}
+		dto.Latitude, dto.Longitude = &result.Latitude, &result.Longitude
+	}
+
+	categoryID, err := s.repository.FindCategoryID(dto.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	merchantID := merchant.ID
+	shop := &models.Shop{
+		MerchantID: &merchantID,
+		Name:       dto.Name,
+		OwnerName:  merchant.Name,
+		Type:       dto.Type,
+		CategoryID: categoryID,
+		Email:      dto.Email,
+		Mobile:     dto.Mobile,
+		Address:    dto.Address,
+		Latitude:   *dto.Latitude,
+		Longitude:  *dto.Longitude,
+		Location: gogis.Point{
+			Lng: *dto.Longitude,
+			Lat: *dto.Latitude,
+		},
+		SupportsDelivery: dto.SupportsDelivery,
+		DeliveryRadius:   dto.DeliveryRadius,
+	}
+	if err := s.repository.CreateShop(shop); err != nil {
+		return nil, err
+	}
+	return shop, nil
+}
+
+// LinkShop brings an existing shop account under the merchant after checking
+// the shop's own credentials.
+func (s *Service) LinkShop(merchantID uint, dto *LinkShopDTORequest) (*models.Shop, error) {
+	shop, err := s.repository.FindShopByEmail(dto.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if err := utils.CheckPasswordHash(dto.Password, shop.Password); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if shop.ClosedAt != nil {
+		return nil, ErrShopClosed
+	}
+
+	if err := s.repository.LinkShop(shop.ID, merchantID); err != nil {
+		return nil, err
+	}
+	shop.MerchantID = &merchantID
+	return shop, nil
+}
+
+// PushPriceList applies one price list to several branches at once.
+func (s *Service) PushPriceList(merchantID uint, dto *PushPriceListDTORequest) ([]PriceListResultDTOResponse, error) {
+	if err := validatePriceList(dto.Items); err != nil {
+		return nil, err
+	}
+
+	shopIDs := dto.ShopIDs
+	if len(shopIDs) == 0 {
+		shops, err := s.repository.GetShops(merchantID)
+		if err != nil {
+			return nil, err
+		}
+		for _, shop := range shops {
+			if shop.ClosedAt == nil && shop.SuspendedAt == nil {
+				shopIDs = append(shopIDs, shop.ID)
+			}
+		}
+		if len(shopIDs) == 0 {
+			return nil, ErrNoOpenShops
+		}
+	} else {
+		shopIDs = uniqueIDs(shopIDs)
+		for _, shopID := range shopIDs {
+			if _, err := s.SelectShop(merchantID, shopID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return s.repository.ApplyPriceList(shopIDs, dto.Items)
+}
+
+func validatePriceList(items []PriceListItemDTO) error {
+	seen := make(map[uint]bool, len(items))
+	for _, item := range items {
+		if seen[item.CatalogID] {
+			return ErrDuplicateCatalogProduct
+		}
+		seen[item.CatalogID] = true
+	}
+	return nil
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// defaultShop is the branch a fresh login starts on: the oldest open one.
+func defaultShop(shops []models.Shop) *models.Shop {
+	for i := range shops {
+		if shops[i].ClosedAt == nil && shops[i].SuspendedAt == nil {
+			return &shops[i]
+		}
+	}
+	return nil
+}
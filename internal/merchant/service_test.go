@@ -0,0 +1,31 @@
+package merchant
+
+import (
+	"shop-near-u/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePriceList(t *testing.T) {
+	assert.NoError(t, validatePriceList([]PriceListItemDTO{{CatalogID: 1}, {CatalogID: 2}}))
+	assert.ErrorIs(t, validatePriceList([]PriceListItemDTO{{CatalogID: 1}, {CatalogID: 1}}), ErrDuplicateCatalogProduct)
+}
+
+func TestDefaultShopSkipsClosedBranches(t *testing.T) {
+	closed := time.Now()
+	shops := []models.Shop{{ID: 1, ClosedAt: &closed}, {ID: 2}, {ID: 3}}
+
+	shop := defaultShop(shops)
+	if assert.NotNil(t, shop) {
+		assert.Equal(t, uint(2), shop.ID)
+	}
+
+	assert.Nil(t, defaultShop([]models.Shop{{ID: 1, ClosedAt: &closed}}))
+	assert.Nil(t, defaultShop(nil))
+}
+
+func TestUniqueIDs(t *testing.T) {
+	assert.Equal(t, []uint{3, 1, 2}, uniqueIDs([]uint{3, 1, 3, 2, 1}))
+}
@@ -38,6 +38,11 @@ func requireUserAuth(c *gin.Context) {
 		c.Abort()
 		return
 	}
+	if user.SuspendedAt != nil {
+		utils.ErrorResponseSimple(c, http.StatusForbidden, "account suspended")
+		c.Abort()
+		return
+	}
 
 	c.Set("user", user)
 	c.Set("role", role)
@@ -45,6 +50,67 @@ func requireUserAuth(c *gin.Context) {
 	c.Next()
 }
 
+// optionalUserAuth loads the user when a valid user cookie is present and
+// otherwise lets the request through anonymously.
+func optionalUserAuth(c *gin.Context) {
+	tokenString, err := c.Cookie("Authorization")
+	if err != nil || tokenString == "" {
+		c.Next()
+		return
+	}
+
+	userID, role, err := utils.ParseToken(tokenString)
+	if err != nil || role != models.RoleUser {
+		c.Next()
+		return
+	}
+
+	var user models.User
+	if err := db.Where("id = ?", userID).First(&user).Error; err == nil && user.ID != 0 && user.SuspendedAt == nil {
+		c.Set("user", user)
+		c.Set("role", role)
+	}
+
+	c.Next()
+}
+
+// optionalShopAuth loads the shop behind an owner or staff cookie when there
+// is one and otherwise lets the request through anonymously.
+func optionalShopAuth(c *gin.Context) {
+	tokenString, err := c.Cookie("Authorization")
+	if err != nil || tokenString == "" {
+		c.Next()
+		return
+	}
+
+	subject, role, err := utils.ParseToken(tokenString)
+	if err != nil {
+		c.Next()
+		return
+	}
+
+	var shopID int64
+	switch role {
+	case models.RoleShopOwner:
+		shopID = subject
+	case models.RoleShopStaff:
+		var staff models.ShopStaff
+		if err := db.Where("id = ? AND accepted_at IS NOT NULL AND removed_at IS NULL", subject).First(&staff).Error; err == nil {
+			shopID = int64(staff.ShopID)
+		}
+	}
+
+	var shop models.Shop
+	if shopID != 0 {
+		if err := db.Where("id = ?", shopID).First(&shop).Error; err == nil && shop.ClosedAt == nil && shop.SuspendedAt == nil {
+			c.Set("shop", shop)
+			c.Set("role", role)
+		}
+	}
+
+	c.Next()
+}
+
 func requireShopOwnerAuth(c *gin.Context) {
 	tokenString, err := c.Cookie("Authorization")
 	if err != nil {
@@ -72,6 +138,27 @@ func requireShopOwnerAuth(c *gin.Context) {
 		c.Abort()
 		return
 	}
+	if shop.ClosedAt != nil {
+		utils.ErrorResponseSimple(c, http.StatusForbidden, "shop closed permanently")
+		c.Abort()
+		return
+	}
+	if shop.SuspendedAt != nil {
+		utils.ErrorResponseSimple(c, http.StatusForbidden, "shop suspended")
+		c.Abort()
+		return
+	}
+
+	// A branch selected through a merchant login must still belong to that merchant.
+	merchantID, err := utils.ParseMerchantID(tokenString)
+	if err != nil || (merchantID != 0 && (shop.MerchantID == nil || *shop.MerchantID != merchantID)) {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "invalid token")
+		c.Abort()
+		return
+	}
+	if merchantID != 0 {
+		c.Set("merchant_id", merchantID)
+	}
 
 	c.Set("shop", shop)
 	c.Set("role", role)
@@ -79,6 +166,128 @@ func requireShopOwnerAuth(c *gin.Context) {
 	c.Next()
 }
 
+// requireShopStaff authenticates an accepted staff member holding the
+// permission and exposes their shop the same way owner auth does.
+func requireShopStaff(c *gin.Context, staffID int64, permission string) {
+	var staff models.ShopStaff
+	if err := db.Where("id = ? AND accepted_at IS NOT NULL AND removed_at IS NULL", staffID).First(&staff).Error; err != nil || staff.ID == 0 {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "staff member not found")
+		c.Abort()
+		return
+	}
+
+	if !staff.HasPermission(permission) {
+		utils.ErrorResponseSimple(c, http.StatusForbidden, "insufficient permissions")
+		c.Abort()
+		return
+	}
+
+	var shop models.Shop
+	if err := db.Where("id = ?", staff.ShopID).First(&shop).Error; err != nil || shop.ID == 0 {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "shop not found")
+		c.Abort()
+		return
+	}
+	if shop.ClosedAt != nil {
+		utils.ErrorResponseSimple(c, http.StatusForbidden, "shop closed permanently")
+		c.Abort()
+		return
+	}
+	if shop.SuspendedAt != nil {
+		utils.ErrorResponseSimple(c, http.StatusForbidden, "shop suspended")
+		c.Abort()
+		return
+	}
+
+	c.Set("shop", shop)
+	c.Set("staff", staff)
+	c.Set("role", models.RoleShopStaff)
+
+	c.Next()
+}
+
+func requireStaffAuth(c *gin.Context) {
+	tokenString, err := c.Cookie("Authorization")
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	staffID, role, err := utils.ParseToken(tokenString)
+
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "invalid token")
+		c.Abort()
+		return
+	}
+
+	var staff models.ShopStaff
+	if err := db.Where("id = ? AND accepted_at IS NOT NULL AND removed_at IS NULL", staffID).First(&staff).Error; err != nil || staff.ID == 0 {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "staff member not found")
+		c.Abort()
+		return
+	}
+	if role != models.RoleShopStaff {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "insufficient permissions")
+		c.Abort()
+		return
+	}
+
+	c.Set("staff", staff)
+	c.Set("role", role)
+
+	c.Next()
+}
+
+// requireMerchantAuth accepts a merchant token, or a shop owner token for a
+// branch selected through the merchant account.
+func requireMerchantAuth(c *gin.Context) {
+	tokenString, err := c.Cookie("Authorization")
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	subject, role, err := utils.ParseToken(tokenString)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "invalid token")
+		c.Abort()
+		return
+	}
+
+	var merchantID uint
+	switch role {
+	case models.RoleMerchant:
+		merchantID = uint(subject)
+	case models.RoleShopOwner:
+		merchantID, err = utils.ParseMerchantID(tokenString)
+		if err != nil {
+			utils.ErrorResponseSimple(c, http.StatusUnauthorized, "invalid token")
+			c.Abort()
+			return
+		}
+	}
+	if merchantID == 0 {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "insufficient permissions")
+		c.Abort()
+		return
+	}
+
+	var merchant models.Merchant
+	if err := db.Where("id = ?", merchantID).First(&merchant).Error; err != nil || merchant.ID == 0 {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "merchant not found")
+		c.Abort()
+		return
+	}
+
+	c.Set("merchant", merchant)
+	c.Set("role", role)
+
+	c.Next()
+}
+
 func requireAdminAuth(c *gin.Context) {
 	tokenString, err := c.Cookie("Authorization")
 	if err != nil {
@@ -102,7 +311,7 @@ func requireAdminAuth(c *gin.Context) {
 		return
 	}
 
-	if role != models.RoleAdmin {
+	if role != models.RoleAdmin || user.Role != models.RoleAdmin || user.SuspendedAt != nil {
 		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "insufficient permissions")
 		c.Abort()
 		return
@@ -118,11 +327,58 @@ func RequireUserAuth(gormDB *gorm.DB) gin.HandlerFunc {
 	return requireUserAuth
 }
 
+func OptionalUserAuth(gormDB *gorm.DB) gin.HandlerFunc {
+	db = gormDB
+	return optionalUserAuth
+}
+
+func OptionalShopAuth(gormDB *gorm.DB) gin.HandlerFunc {
+	db = gormDB
+	return optionalShopAuth
+}
+
 func RequireShopOwnerAuth(gormDB *gorm.DB) gin.HandlerFunc {
 	db = gormDB
 	return requireShopOwnerAuth
 }
 
+// RequireShopPermission lets the shop owner through, as well as staff members
+// of the shop who were granted the permission.
+func RequireShopPermission(gormDB *gorm.DB, permission string) gin.HandlerFunc {
+	db = gormDB
+	return func(c *gin.Context) {
+		tokenString, err := c.Cookie("Authorization")
+		if err != nil {
+			utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+			c.Abort()
+			return
+		}
+
+		subject, role, err := utils.ParseToken(tokenString)
+		if err != nil {
+			utils.ErrorResponseSimple(c, http.StatusUnauthorized, "invalid token")
+			c.Abort()
+			return
+		}
+
+		if role == models.RoleShopStaff {
+			requireShopStaff(c, subject, permission)
+			return
+		}
+		requireShopOwnerAuth(c)
+	}
+}
+
+func RequireStaffAuth(gormDB *gorm.DB) gin.HandlerFunc {
+	db = gormDB
+	return requireStaffAuth
+}
+
+func RequireMerchantAuth(gormDB *gorm.DB) gin.HandlerFunc {
+	db = gormDB
+	return requireMerchantAuth
+}
+
 func RequireAdminAuth(gormDB *gorm.DB) gin.HandlerFunc {
 	db = gormDB
 	return requireAdminAuth
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// UserAddress is a labelled location a user has saved, such as "home" or
+// "work". At most one address per user is the default; its coordinates are
+// mirrored onto User.Latitude and User.Longitude.
+type UserAddress struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint      `gorm:"not null;index;uniqueIndex:idx_user_default_address,where:is_default" json:"user_id"`
+	Label     string    `gorm:"type:varchar(50);not null" json:"label"`
+	Address   string    `gorm:"type:varchar(255);not null" json:"address"`
+	Latitude  float64   `gorm:"type:decimal(10,8);not null" json:"latitude"`
+	Longitude float64   `gorm:"type:decimal(11,8);not null" json:"longitude"`
+	IsDefault bool      `gorm:"type:boolean;default:false" json:"is_default"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
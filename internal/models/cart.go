@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type CartItem struct {
+	ID            uint `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID        uint `gorm:"not null;uniqueIndex:idx_cart_user_product" json:"user_id"`
+	ShopProductID uint `gorm:"not null;uniqueIndex:idx_cart_user_product" json:"shop_product_id"`
+	Quantity      int  `gorm:"not null" json:"quantity"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	ShopProduct ShopProduct `gorm:"foreignKey:ShopProductID" json:"shop_product"`
+}
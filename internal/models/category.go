@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// CategorySubtreeSQL selects the IDs of the category with the given slug and
+// all of its descendants.
+const CategorySubtreeSQL = `SELECT d.id FROM categories c JOIN categories d ON d.path LIKE c.path || '%' WHERE c.slug = ?`
+
+// Category is a node in the managed product and shop taxonomy. Path lists
+// the IDs from the root down to the category itself, as in "/1/4/", so a
+// subtree is every category whose path starts with the node's path.
+type Category struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	ParentID *uint  `gorm:"index" json:"parent_id,omitempty"`
+	Name     string `gorm:"type:varchar(100);not null" json:"name"`
+	Slug     string `gorm:"type:varchar(120);not null;uniqueIndex" json:"slug"`
+	Path     string `gorm:"type:varchar(255);not null;index" json:"-"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// CategoryPath returns the path of a category with the given ID placed under
+// parentPath, which is empty for a root category.
+func CategoryPath(parentPath string, id uint) string {
+	if parentPath == "" {
+		parentPath = "/"
+	}
+	return parentPath + strconv.FormatUint(uint64(id), 10) + "/"
+}
+
+// Slugify turns a category name into its URL slug: lower case letters and
+// digits with single hyphens between words, so "Dairy", "dairy " and
+// "DAIRY" share the slug "dairy".
+func Slugify(name string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingHyphen = true
+	}
+	return b.String()
+}
+
+// ShopTypeCategoryID returns the ID of the category whose slug matches the
+// shop type, or nil when the type is not in the category tree yet.
+func ShopTypeCategoryID(db *gorm.DB, shopType string) (*uint, error) {
+	var category Category
+	err := db.Where("slug = ?", Slugify(shopType)).First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category.ID, nil
+}
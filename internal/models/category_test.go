@@ -0,0 +1,20 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "dairy", Slugify("Dairy"))
+	assert.Equal(t, "dairy", Slugify(" dairy "))
+	assert.Equal(t, "milk-products", Slugify("Milk  Products"))
+	assert.Equal(t, "fruits-vegetables", Slugify("Fruits & Vegetables!"))
+	assert.Equal(t, "", Slugify("--"))
+}
+
+func TestCategoryPath(t *testing.T) {
+	assert.Equal(t, "/3/", CategoryPath("", 3))
+	assert.Equal(t, "/1/4/", CategoryPath("/1/", 4))
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	InventoryActionCreated = "created"
+	InventoryActionUpdated = "updated"
+	InventoryActionDeleted = "deleted"
+)
+
+// InventoryChange records one edit to a shop's product listing. StaffID is
+// nil when the owner made the change.
+type InventoryChange struct {
+	ID            uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	ShopID        uint   `gorm:"not null;index" json:"shop_id"`
+	ShopProductID uint   `gorm:"not null;index" json:"shop_product_id"`
+	StaffID       *uint  `gorm:"index" json:"staff_id,omitempty"`
+	Action        string `gorm:"type:varchar(20);not null" json:"action"`
+
+	StockBefore int     `json:"stock_before"`
+	StockAfter  int     `json:"stock_after"`
+	PriceBefore float64 `gorm:"type:decimal(10,2)" json:"price_before"`
+	PriceAfter  float64 `gorm:"type:decimal(10,2)" json:"price_after"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	Staff *ShopStaff `gorm:"foreignKey:StaffID" json:"staff,omitempty"`
+}
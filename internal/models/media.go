@@ -0,0 +1,101 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ImageVariantThumb = "thumb"
+	ImageVariantCard  = "card"
+	ImageVariantFull  = "full"
+)
+
+// ImageVariantSizes gives the longest edge, in pixels, of each variant
+// generated on upload. Smaller images are never scaled up.
+var ImageVariantSizes = []struct {
+	Name    string
+	MaxEdge int
+}{
+	{ImageVariantThumb, 160},
+	{ImageVariantCard, 480},
+	{ImageVariantFull, 1600},
+}
+
+// Image is an uploaded picture. Key, ContentType, Size, Width and Height
+// describe the full variant that GET /api/images/:id serves; Checksum is of
+// the original upload. Each uploader gets its own row, but rows with the same
+// checksum share the stored files.
+type Image struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Key         string `gorm:"type:varchar(255);not null;index" json:"-"`
+	ContentType string `gorm:"type:varchar(50);not null" json:"content_type"`
+	Size        int64  `gorm:"not null" json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Checksum    string `gorm:"type:char(64);not null;index" json:"-"`
+
+	UploadedByShopID *uint `gorm:"index" json:"uploaded_by_shop_id,omitempty"`
+	UploadedByUserID *uint `json:"uploaded_by_user_id,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	Variants []ImageVariant `gorm:"foreignKey:ImageID" json:"-"`
+}
+
+// ImageVariant is a resized, re-encoded copy of an Image.
+type ImageVariant struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	ImageID     uint   `gorm:"not null;uniqueIndex:idx_image_variant" json:"image_id"`
+	Name        string `gorm:"type:varchar(20);not null;uniqueIndex:idx_image_variant" json:"name"`
+	Key         string `gorm:"type:varchar(255);not null" json:"-"`
+	ContentType string `gorm:"type:varchar(50);not null" json:"content_type"`
+	Size        int64  `gorm:"not null" json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+}
+
+// ImageVariantURLs lists where each variant of an image is served.
+type ImageVariantURLs struct {
+	Thumb string `json:"thumb"`
+	Card  string `json:"card"`
+	Full  string `json:"full"`
+}
+
+func NewImageVariantURLs(id uint) *ImageVariantURLs {
+	base := ImageURL(id)
+	return &ImageVariantURLs{
+		Thumb: base + "/" + ImageVariantThumb,
+		Card:  base + "/" + ImageVariantCard,
+		Full:  base + "/" + ImageVariantFull,
+	}
+}
+
+// ImageURL is the path the API serves an image from.
+func ImageURL(id uint) string {
+	return "/api/images/" + strconv.FormatUint(uint64(id), 10)
+}
+
+// ShopImage places an uploaded image in a shop's gallery.
+type ShopImage struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	ShopID   uint   `gorm:"not null;index" json:"shop_id"`
+	ImageID  uint   `gorm:"not null" json:"image_id"`
+	Caption  string `gorm:"type:varchar(255)" json:"caption"`
+	Position int    `gorm:"not null;default:0" json:"position"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// Images holds the variant URLs, filled in on load.
+	Images *ImageVariantURLs `gorm:"-" json:"images"`
+
+	Image Image `gorm:"foreignKey:ImageID" json:"-"`
+}
+
+// AfterFind fills in the variant URLs of the gallery image.
+func (i *ShopImage) AfterFind(tx *gorm.DB) error {
+	i.Images = NewImageVariantURLs(i.ImageID)
+	return nil
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Merchant is an owner account that can run several shops. Its branches are
+// the shops whose MerchantID points at it.
+type Merchant struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	Email     string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"email"`
+	Password  string    `gorm:"type:varchar(255);not null" json:"-"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	Shops []Shop `gorm:"foreignKey:MerchantID" json:"shops,omitempty"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+const (
+	FulfilmentPickup   = "pickup"
+	FulfilmentDelivery = "delivery"
+)
+
+const (
+	OrderStatusPlaced    = "placed"
+	OrderStatusAccepted  = "accepted"
+	OrderStatusReady     = "ready"
+	OrderStatusCompleted = "completed"
+	OrderStatusRejected  = "rejected"
+	OrderStatusCancelled = "cancelled"
+)
+
+type Order struct {
+	ID     uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID uint   `gorm:"not null;index" json:"user_id"`
+	ShopID uint   `gorm:"not null;index" json:"shop_id"`
+	Status string `gorm:"type:varchar(20);not null;default:'placed';index" json:"status"`
+
+	Subtotal    float64 `gorm:"type:decimal(10,2);not null" json:"subtotal"`
+	Discount    float64 `gorm:"type:decimal(10,2);default:0" json:"discount"`
+	DeliveryFee float64 `gorm:"type:decimal(10,2);default:0" json:"delivery_fee"`
+	Total       float64 `gorm:"type:decimal(10,2);not null" json:"total"`
+
+	FulfilmentType    string  `gorm:"type:varchar(20);not null;default:'pickup'" json:"fulfilment_type"`
+	DeliveryAddress   string  `gorm:"type:varchar(255)" json:"delivery_address,omitempty"`
+	DeliveryLatitude  float64 `gorm:"type:decimal(10,8);" json:"delivery_latitude,omitempty"`
+	DeliveryLongitude float64 `gorm:"type:decimal(11,8);" json:"delivery_longitude,omitempty"`
+	DeliveryDistance  float64 `gorm:"type:decimal(10,2);" json:"delivery_distance,omitempty"`
+
+	Reason string `gorm:"type:varchar(255)" json:"reason,omitempty"`
+
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+	ReadyAt     *time.Time `json:"ready_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	RejectedAt  *time.Time `json:"rejected_at,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Items []OrderItem `gorm:"foreignKey:OrderID" json:"items"`
+}
+
+type OrderItem struct {
+	ID            uint `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID       uint `gorm:"not null;index" json:"order_id"`
+	ShopProductID uint `gorm:"not null;index" json:"shop_product_id"`
+	CatalogID     uint `gorm:"not null" json:"catalog_id"`
+
+	Name      string  `gorm:"type:varchar(100);not null" json:"name"`
+	UnitPrice float64 `gorm:"type:decimal(10,2);not null" json:"unit_price"`
+	Discount  float64 `gorm:"type:decimal(5,2);default:0" json:"discount"`
+	Quantity  int     `gorm:"not null" json:"quantity"`
+	LineTotal float64 `gorm:"type:decimal(10,2);not null" json:"line_total"`
+}
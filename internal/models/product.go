@@ -1,22 +1,181 @@
 package models
 
 import (
+	"math"
+	"strings"
 	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+const (
+	CatalogStatusPending  = "pending"
+	CatalogStatusApproved = "approved"
+	CatalogStatusRejected = "rejected"
 )
 
 type CatalogProduct struct {
-	ID         uint   `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name       string `gorm:"type:varchar(100);not null;index" json:"name"`
-	Brand      string `gorm:"type:varchar(100);index" json:"brand"`
-	Category   string `gorm:"type:varchar(100);index" json:"category"`
-	Desciption string `gorm:"type:text" json:"description"`
+	ID          uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string `gorm:"type:varchar(100);not null;index" json:"name"`
+	Brand       string `gorm:"type:varchar(100);index" json:"brand"`
+	Category    string `gorm:"type:varchar(100);index" json:"category"`
+	CategoryID  *uint  `gorm:"index" json:"category_id,omitempty"`
+	Description string `gorm:"type:text" json:"description"`
 
+	// ImageURL is either an external link or, when ImageID is set, the path
+	// of the uploaded image.
 	ImageURL string `gorm:"type:varchar(255)" json:"image_url"`
+	ImageID  *uint  `json:"image_id,omitempty"`
+	// Images holds the variant URLs of the uploaded image, filled in on load.
+	Images *ImageVariantURLs `gorm:"-" json:"images,omitempty"`
+
+	// Status is pending until an admin reviews a shop's submission. Pending
+	// products can only be listed by the shop that submitted them.
+	Status            string     `gorm:"type:varchar(20);not null;default:'approved';index" json:"status"`
+	SubmittedByShopID *uint      `gorm:"index" json:"submitted_by_shop_id,omitempty"`
+	ReviewedByID      *uint      `json:"reviewed_by_id,omitempty"`
+	ReviewedAt        *time.Time `json:"reviewed_at,omitempty"`
+	RejectionReason   string     `gorm:"type:varchar(255)" json:"rejection_reason,omitempty"`
+
+	// MatchKey is the normalised name/brand/category used to spot duplicates.
+	MatchKey string `gorm:"type:varchar(320);index" json:"-"`
+
+	// RetiredAt is set once the product is withdrawn from the catalog. Retired
+	// products stay readable for order history but cannot be listed again.
+	RetiredAt *time.Time `gorm:"index" json:"retired_at,omitempty"`
 
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 
-	ShopProducts []ShopProduct `gorm:"foreignKey:CatalogID" json:"shop_products"`
+	ShopProducts []ShopProduct    `gorm:"foreignKey:CatalogID" json:"shop_products,omitempty"`
+	Barcodes     []CatalogBarcode `gorm:"foreignKey:CatalogProductID" json:"barcodes,omitempty"`
+}
+
+// CatalogBarcode is one GTIN printed on a catalog product. A product may
+// carry several, one per pack size. GTIN is stored in its 14-digit form and
+// is unique across the catalog.
+type CatalogBarcode struct {
+	ID               uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	CatalogProductID uint   `gorm:"not null;index" json:"catalog_product_id"`
+	GTIN             string `gorm:"column:gtin;type:char(14);not null;uniqueIndex" json:"gtin"`
+	PackSize         string `gorm:"type:varchar(50)" json:"pack_size,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// UsableBy reports whether the shop may list the product: approved products
+// are open to everyone, pending ones only to the shop that submitted them,
+// and retired ones to no one.
+func (p *CatalogProduct) UsableBy(shopID uint) bool {
+	if p.RetiredAt != nil {
+		return false
+	}
+	switch p.Status {
+	case CatalogStatusApproved:
+		return true
+	case CatalogStatusPending:
+		return p.SubmittedByShopID != nil && *p.SubmittedByShopID == shopID
+	default:
+		return false
+	}
+}
+
+// AfterFind fills in the variant URLs of an uploaded image.
+func (p *CatalogProduct) AfterFind(tx *gorm.DB) error {
+	if p.ImageID != nil {
+		p.Images = NewImageVariantURLs(*p.ImageID)
+	}
+	return nil
+}
+
+// BeforeSave keeps MatchKey in step with the fields it is derived from.
+func (p *CatalogProduct) BeforeSave(tx *gorm.DB) error {
+	p.MatchKey = CatalogMatchKey(p.Name, p.Brand, p.Category)
+	return nil
+}
+
+// unitAliases folds the common spellings of pack-size units together.
+var unitAliases = map[string]string{
+	"gm": "g", "gms": "g", "gram": "g", "grams": "g", "gr": "g",
+	"kgs": "kg", "kilo": "kg", "kilogram": "kg", "kilograms": "kg",
+	"ltr": "l", "ltrs": "l", "litre": "l", "litres": "l", "liter": "l", "liters": "l",
+	"mls": "ml", "millilitre": "ml", "milliliter": "ml",
+	"pcs": "pc", "piece": "pc", "pieces": "pc",
+}
+
+// CatalogMatchKey normalises a product's name, brand and category so that
+// "Amul Butter 500g" and "amul butter 500 gm" produce the same key. Case,
+// spacing and punctuation are ignored and unit spellings are folded.
+func CatalogMatchKey(name, brand, category string) string {
+	return normaliseMatchText(name) + "|" + normaliseMatchText(brand) + "|" + normaliseMatchText(category)
+}
+
+func normaliseMatchText(text string) string {
+	var tokens []string
+	var current []rune
+	var currentIsDigit bool
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		token := string(current)
+		if alias, ok := unitAliases[token]; ok {
+			token = alias
+		}
+		tokens = append(tokens, token)
+		current = current[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			isDigit := unicode.IsDigit(r)
+			if len(current) > 0 && isDigit != currentIsDigit {
+				flush()
+			}
+			current = append(current, r)
+			currentIsDigit = isDigit
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return strings.Join(tokens, "")
+}
+
+// VisibleTo reports whether shopID may read the product. Approved products
+// are public; pending and rejected ones are visible only to their submitter.
+// shopID is 0 for anonymous callers.
+func (p *CatalogProduct) VisibleTo(shopID uint) bool {
+	if p.Status == CatalogStatusApproved {
+		return true
+	}
+	return shopID != 0 && p.SubmittedByShopID != nil && *p.SubmittedByShopID == shopID
+}
+
+// CatalogProductRevision records one edit to a catalog product. Changes is a
+// JSON object mapping each edited field to its old and new value. Exactly one
+// of EditedByUserID (an admin) and EditedByShopID is set.
+type CatalogProductRevision struct {
+	ID               uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	CatalogProductID uint   `gorm:"not null;index" json:"catalog_product_id"`
+	EditedByUserID   *uint  `json:"edited_by_user_id,omitempty"`
+	EditedByShopID   *uint  `json:"edited_by_shop_id,omitempty"`
+	Changes          string `gorm:"type:jsonb;not null" json:"-"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// CatalogRedirect points a merged-away catalog product ID at the product
+// that absorbed it, so old links and client caches keep working.
+type CatalogRedirect struct {
+	FromID     uint      `gorm:"primaryKey;autoIncrement:false" json:"from_id"`
+	ToID       uint      `gorm:"not null;index" json:"to_id"`
+	MergedByID uint      `json:"merged_by_id"`
+	MergedAt   time.Time `gorm:"not null" json:"merged_at"`
 }
 
 type ShopProduct struct {
@@ -35,3 +194,10 @@ type ShopProduct struct {
 	Shop           Shop           `gorm:"foreignKey:ShopID" json:"shop"`
 	CatalogProduct CatalogProduct `gorm:"foreignKey:CatalogID" json:"catalog_product"`
 }
+
+// EffectivePrice returns the unit price after applying the percentage discount,
+// rounded to two decimal places.
+func (p *ShopProduct) EffectivePrice() float64 {
+	price := p.Price * (1 - p.Discount/100)
+	return math.Round(price*100) / 100
+}
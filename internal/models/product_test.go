@@ -0,0 +1,47 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogProductUsableBy(t *testing.T) {
+	submitter := uint(7)
+
+	approved := CatalogProduct{Status: CatalogStatusApproved}
+	assert.True(t, approved.UsableBy(1))
+
+	pending := CatalogProduct{Status: CatalogStatusPending, SubmittedByShopID: &submitter}
+	assert.True(t, pending.UsableBy(7))
+	assert.False(t, pending.UsableBy(1))
+
+	rejected := CatalogProduct{Status: CatalogStatusRejected, SubmittedByShopID: &submitter}
+	assert.False(t, rejected.UsableBy(7))
+}
+
+func TestCatalogMatchKey(t *testing.T) {
+	key := CatalogMatchKey("Amul Butter 500g", "Amul", "Dairy")
+	assert.Equal(t, key, CatalogMatchKey("amul butter 500 g", "AMUL", "dairy"))
+	assert.Equal(t, key, CatalogMatchKey("Amul  Butter, 500 gms", " Amul ", "Dairy"))
+	assert.Equal(t, "milk1l|amul|dairy", CatalogMatchKey("Milk 1 Ltr", "Amul", "Dairy"))
+
+	assert.NotEqual(t, key, CatalogMatchKey("Amul Butter 100g", "Amul", "Dairy"))
+	assert.NotEqual(t, key, CatalogMatchKey("Amul Butter 500g", "Mother Dairy", "Dairy"))
+}
+
+func TestCatalogProductVisibleTo(t *testing.T) {
+	submitter := uint(3)
+	now := time.Now()
+
+	assert.True(t, (&CatalogProduct{Status: CatalogStatusApproved}).VisibleTo(0))
+
+	rejected := CatalogProduct{Status: CatalogStatusRejected, SubmittedByShopID: &submitter}
+	assert.True(t, rejected.VisibleTo(3))
+	assert.False(t, rejected.VisibleTo(0))
+
+	retired := CatalogProduct{Status: CatalogStatusApproved, RetiredAt: &now}
+	assert.True(t, retired.VisibleTo(0))
+	assert.False(t, retired.UsableBy(1))
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	ReservationStatusActive    = "active"
+	ReservationStatusExpired   = "expired"
+	ReservationStatusCancelled = "cancelled"
+	ReservationStatusConverted = "converted"
+)
+
+// StockReservation holds units of a ShopProduct for in-store pickup. The held
+// quantity is taken out of ShopProduct.Stock while the hold is active and
+// returned when it expires or is cancelled.
+type StockReservation struct {
+	ID            uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	ShopProductID uint   `gorm:"not null;index" json:"shop_product_id"`
+	ShopID        uint   `gorm:"not null;index" json:"shop_id"`
+	UserID        uint   `gorm:"not null;index" json:"user_id"`
+	Quantity      int    `gorm:"not null" json:"quantity"`
+	Status        string `gorm:"type:varchar(20);not null;default:'active';index:idx_reservation_status_expiry" json:"status"`
+
+	ExpiresAt   time.Time  `gorm:"not null;index:idx_reservation_status_expiry" json:"expires_at"`
+	OrderID     *uint      `json:"order_id,omitempty"`
+	ConvertedAt *time.Time `json:"converted_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	ShopProduct ShopProduct `gorm:"foreignKey:ShopProductID" json:"shop_product"`
+}
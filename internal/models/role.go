@@ -4,4 +4,6 @@ const (
 	RoleUser      = "user"
 	RoleShopOwner = "shop_owner"
 	RoleAdmin     = "admin"
+	RoleMerchant  = "merchant"
+	RoleShopStaff = "shop_staff"
 )
@@ -9,30 +9,129 @@ import (
 type Shop struct {
 	ID uint `gorm:"primaryKey;autoIncrement" json:"id"`
 
+	// MerchantID links a branch to the merchant account that owns it. Shops
+	// registered on their own have none.
+	MerchantID *uint `gorm:"index" json:"merchant_id,omitempty"`
+
 	Name             string `gorm:"type:varchar(100);not null" json:"name"`
 	OwnerName        string `gorm:"type:varchar(100);not null" json:"owner_name"`
 	Email            string `gorm:"type:varchar(100);uniqueIndex;not null" json:"email"`
 	Mobile           string `gorm:"type:varchar(15);not null" json:"mobile"`
 	Type             string `gorm:"type:varchar(50);not null" json:"type"`
+	CategoryID       *uint  `gorm:"index" json:"category_id,omitempty"`
 	SupportsDelivery bool   `gorm:"type:boolean;default:false" json:"supports_delivery"`
 	Password         string `gorm:"type:varchar(255);not null" json:"-"`
 
 	Address   string  `gorm:"type:varchar(255);not null" json:"address"`
 	Latitude  float64 `gorm:"type:decimal(10,8);" json:"latitude"`
-	Longitude float64 `gorm:"type:decimal(10,8);" json:"longitude"`
+	Longitude float64 `gorm:"type:decimal(11,8);" json:"longitude"`
 	Location      gogis.Point `gorm:"type:geometry(POINT,4326);" json:"location"`
 	SubscriberCount uint        `gorm:"type:int;default:0" json:"subscriber_count"`
 	IsOpen        bool          `gorm:"type:boolean;default:true" json:"is_open"`
 
+	// TimeZone is the IANA zone the opening hours are written in. While
+	// StatusOverrideUntil is in the future, IsOpen is used as-is instead of
+	// the schedule.
+	TimeZone            string     `gorm:"type:varchar(64);not null;default:'UTC'" json:"time_zone"`
+	StatusOverrideUntil *time.Time `json:"status_override_until,omitempty"`
+
+	DeliveryRadius    float64 `gorm:"type:decimal(10,2);default:0" json:"delivery_radius"`
+	FreeDeliveryAbove float64 `gorm:"type:decimal(10,2);default:0" json:"free_delivery_above"`
+
+	// ServiceArea is the polygon the shop delivers to. When set it takes the
+	// place of DeliveryRadius. It is only read and written through raw PostGIS
+	// queries, so GORM never loads or saves it.
+	ServiceArea string `gorm:"type:geometry(MULTIPOLYGON,4326);index:idx_shops_service_area,type:gist;->:false;<-:false" json:"-"`
+
+	// RelocatedAt is when the shop last changed address, if ever.
+	RelocatedAt *time.Time `json:"relocated_at,omitempty"`
+	// ClosedAt is set when the owner closes the account. The row is kept so
+	// subscribers can see that the shop has closed permanently.
+	ClosedAt *time.Time `gorm:"index" json:"closed_at,omitempty"`
+	// SuspendedAt is set while an admin has suspended the shop; it is hidden
+	// from customers and its owner and staff cannot sign in.
+	SuspendedAt      *time.Time `gorm:"index" json:"suspended_at,omitempty"`
+	SuspensionReason string     `gorm:"type:varchar(255)" json:"suspension_reason,omitempty"`
+
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 
-	ShopProducts []ShopProduct `gorm:"foreignKey:ShopID" json:"shop_products"`
+	ShopProducts     []ShopProduct     `gorm:"foreignKey:ShopID" json:"shop_products"`
+	DeliveryFeeTiers []DeliveryFeeTier `gorm:"foreignKey:ShopID" json:"delivery_fee_tiers,omitempty"`
+}
+
+// ShopAddressHistory keeps an address the shop used before it moved.
+type ShopAddressHistory struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ShopID    uint      `gorm:"not null;index" json:"shop_id"`
+	Address   string    `gorm:"type:varchar(255);not null" json:"address"`
+	Latitude  float64   `gorm:"type:decimal(10,8);" json:"latitude"`
+	Longitude float64   `gorm:"type:decimal(11,8);" json:"longitude"`
+	MovedAt   time.Time `gorm:"not null" json:"moved_at"`
 }
 
+// DeliveryFeeTier charges Fee for deliveries up to MaxDistance metres from the shop.
+type DeliveryFeeTier struct {
+	ID          uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ShopID      uint    `gorm:"not null;index" json:"shop_id"`
+	MaxDistance float64 `gorm:"type:decimal(10,2);not null" json:"max_distance"`
+	Fee         float64 `gorm:"type:decimal(10,2);not null" json:"fee"`
+}
+
+// ShopOpenNowSQL derives whether the shop aliased as s is open right now. An
+// unexpired manual override wins, then a date exception for today, then the
+// weekly schedule. Shops without any schedule keep the manual is_open flag.
+const ShopOpenNowSQL = `
+    CASE
+        WHEN s.status_override_until IS NOT NULL AND s.status_override_until > NOW() THEN s.is_open
+        WHEN EXISTS (
+            SELECT 1 FROM shop_hours_exceptions e
+            WHERE e.shop_id = s.id AND e.date = to_char(NOW() AT TIME ZONE s.time_zone, 'YYYY-MM-DD')
+        ) THEN EXISTS (
+            SELECT 1 FROM shop_hours_exceptions e
+            WHERE e.shop_id = s.id
+                AND e.date = to_char(NOW() AT TIME ZONE s.time_zone, 'YYYY-MM-DD')
+                AND NOT e.closed
+                AND to_char(NOW() AT TIME ZONE s.time_zone, 'HH24:MI') >= e.opens_at
+                AND to_char(NOW() AT TIME ZONE s.time_zone, 'HH24:MI') < e.closes_at
+        )
+        WHEN EXISTS (SELECT 1 FROM shop_opening_hours h WHERE h.shop_id = s.id) THEN EXISTS (
+            SELECT 1 FROM shop_opening_hours h
+            WHERE h.shop_id = s.id
+                AND h.weekday = EXTRACT(DOW FROM NOW() AT TIME ZONE s.time_zone)
+                AND to_char(NOW() AT TIME ZONE s.time_zone, 'HH24:MI') >= h.opens_at
+                AND to_char(NOW() AT TIME ZONE s.time_zone, 'HH24:MI') < h.closes_at
+        )
+        ELSE s.is_open
+    END`
+
+// ShopOpeningHours is one open interval on a weekday (0 = Sunday). Times are
+// "HH:MM" in the shop's time zone; ClosesAt may be "24:00".
+type ShopOpeningHours struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	ShopID   uint   `gorm:"not null;index" json:"shop_id"`
+	Weekday  int    `gorm:"not null" json:"weekday"`
+	OpensAt  string `gorm:"type:varchar(5);not null" json:"opens_at"`
+	ClosesAt string `gorm:"type:varchar(5);not null" json:"closes_at"`
+}
+
+// ShopHoursException replaces the weekly schedule on one date ("YYYY-MM-DD").
+// A Closed row closes the shop all day; otherwise each row is an open interval.
+type ShopHoursException struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	ShopID   uint   `gorm:"not null;index:idx_shop_exception_date" json:"shop_id"`
+	Date     string `gorm:"type:varchar(10);not null;index:idx_shop_exception_date" json:"date"`
+	Closed   bool   `gorm:"type:boolean;default:false" json:"closed"`
+	OpensAt  string `gorm:"type:varchar(5)" json:"opens_at,omitempty"`
+	ClosesAt string `gorm:"type:varchar(5)" json:"closes_at,omitempty"`
+	Note     string `gorm:"type:varchar(255)" json:"note,omitempty"`
+}
 
 type ShopSubscription struct {
 	ID 	  uint      `gorm:"primaryKey;autoIncrement" json:"id"`
 	ShopID uint      `gorm:"not null;index" json:"shop_id"`
 	UserID uint      `gorm:"not null;index" json:"user_id"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	// ArchivedAt is set when the shop closes; archived rows no longer count
+	// towards the shop's subscribers.
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
 }
\ No newline at end of file
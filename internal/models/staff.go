@@ -0,0 +1,61 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	PermissionManageProducts = "manage_products"
+	PermissionToggleStatus   = "toggle_status"
+	PermissionViewOrders     = "view_orders"
+)
+
+// StaffPermissions lists every permission an owner can grant.
+var StaffPermissions = []string{
+	PermissionManageProducts,
+	PermissionToggleStatus,
+	PermissionViewOrders,
+}
+
+// ShopStaff is a person invited by a shop owner to help run the shop. The
+// account is usable once the invite is accepted and a password is set, and
+// until the owner removes it.
+type ShopStaff struct {
+	ID     uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	ShopID uint   `gorm:"not null;uniqueIndex:idx_shop_staff_email" json:"shop_id"`
+	Email  string `gorm:"type:varchar(100);not null;uniqueIndex:idx_shop_staff_email" json:"email"`
+	Name   string `gorm:"type:varchar(100)" json:"name"`
+
+	Password string `gorm:"type:varchar(255)" json:"-"`
+	// Permissions is a comma-separated list of the Permission constants.
+	Permissions string `gorm:"type:varchar(255);not null;default:''" json:"-"`
+
+	// InviteTokenHash is the SHA-256 of the token sent with the invite.
+	InviteTokenHash string     `gorm:"type:varchar(64);index" json:"-"`
+	InviteExpiresAt *time.Time `json:"invite_expires_at,omitempty"`
+	AcceptedAt      *time.Time `json:"accepted_at,omitempty"`
+
+	// RemovedAt is set when the owner removes the staff member. The row is
+	// kept so the inventory log still names who made each change.
+	RemovedAt *time.Time `gorm:"index" json:"removed_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (s *ShopStaff) PermissionList() []string {
+	if s.Permissions == "" {
+		return []string{}
+	}
+	return strings.Split(s.Permissions, ",")
+}
+
+func (s *ShopStaff) HasPermission(permission string) bool {
+	for _, p := range s.PermissionList() {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
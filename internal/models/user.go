@@ -8,7 +8,11 @@ type User struct {
 	Email     string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"email"`
 	Password  string    `gorm:"type:varchar(255);not null" json:"-"`
 	Latitude  float64   `gorm:"type:decimal(10,8);" json:"latitude"`
-	Longitude float64   `gorm:"type:decimal(10,8);" json:"longitude"`
+	Longitude float64   `gorm:"type:decimal(11,8);" json:"longitude"`
 	Role      string    `gorm:"type:varchar(50);not null;default:'user'" json:"role"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// SuspendedAt is set while an admin has suspended the account.
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspensionReason string     `gorm:"type:varchar(255)" json:"suspension_reason,omitempty"`
 }
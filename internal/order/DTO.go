@@ -0,0 +1,29 @@
+package order
+
+type PlaceOrderItemDTO struct {
+	ShopProductID uint `json:"shop_product_id" binding:"required"`
+	Quantity      int  `json:"quantity" binding:"required,gt=0"`
+}
+
+type DeliveryAddressDTO struct {
+	Address   string   `json:"address" binding:"required"`
+	Latitude  *float64 `json:"latitude" binding:"required,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" binding:"required,gte=-180,lte=180"`
+}
+
+type PlaceOrderDTORequest struct {
+	ShopID uint                `json:"shop_id" binding:"required"`
+	Items  []PlaceOrderItemDTO `json:"items" binding:"required,min=1,dive"`
+
+	FulfilmentType  string              `json:"fulfilment_type" binding:"omitempty,oneof=pickup delivery"`
+	DeliveryAddress *DeliveryAddressDTO `json:"delivery_address"`
+}
+
+type UpdateOrderStatusDTORequest struct {
+	Status string `json:"status" binding:"required,oneof=accepted ready completed rejected cancelled"`
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+type CancelOrderDTORequest struct {
+	Reason string `json:"reason" binding:"max=255"`
+}
@@ -0,0 +1,258 @@
+package order
+
+import (
+	"errors"
+	"net/http"
+	"shop-near-u/internal/middlewares"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Controller struct {
+	service *Service
+}
+
+func NewController(s *Service) *Controller {
+	return &Controller{service: s}
+}
+
+func (ctrl *Controller) PlaceOrder(c *gin.Context) {
+	var dto PlaceOrderDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	order, err := ctrl.service.PlaceOrder(&dto, u.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponseSimple(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, ErrInsufficientStock):
+			utils.ErrorResponseSimple(c, http.StatusConflict, err.Error())
+		case errors.Is(err, ErrProductUnavailable), errors.Is(err, ErrProductNotInShop),
+			errors.Is(err, ErrDeliveryNotSupported), errors.Is(err, ErrDeliveryAddressRequired),
+			errors.Is(err, ErrOutsideDeliveryRadius):
+			utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		default:
+			utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Order placed successfully", order)
+}
+
+func (ctrl *Controller) GetMyOrders(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	orders, err := ctrl.service.GetUserOrders(u.ID)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Orders retrieved successfully", orders)
+}
+
+func (ctrl *Controller) GetOrder(c *gin.Context) {
+	orderID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid order ID")
+		return
+	}
+
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	order, err := ctrl.service.GetUserOrder(orderID, u.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, ErrOrderNotFound) {
+			utils.ErrorResponseSimple(c, http.StatusNotFound, "order not found")
+			return
+		}
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order retrieved successfully", order)
+}
+
+func (ctrl *Controller) CancelOrder(c *gin.Context) {
+	orderID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid order ID")
+		return
+	}
+
+	var dto CancelOrderDTORequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&dto); err != nil {
+			utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	order, err := ctrl.service.CancelOrder(orderID, u.ID, dto.Reason)
+	if err != nil {
+		transitionErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order cancelled successfully", order)
+}
+
+func (ctrl *Controller) GetShopOrders(c *gin.Context) {
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, "failed to parse shop data")
+		return
+	}
+
+	orders, err := ctrl.service.GetShopOrders(shop.ID, c.Query("status"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Orders retrieved successfully", orders)
+}
+
+func (ctrl *Controller) GetShopOrder(c *gin.Context) {
+	orderID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid order ID")
+		return
+	}
+
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, "failed to parse shop data")
+		return
+	}
+
+	order, err := ctrl.service.GetShopOrder(orderID, shop.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, ErrOrderNotFound) {
+			utils.ErrorResponseSimple(c, http.StatusNotFound, "order not found")
+			return
+		}
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order retrieved successfully", order)
+}
+
+func (ctrl *Controller) UpdateOrderStatus(c *gin.Context) {
+	orderID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid order ID")
+		return
+	}
+
+	var dto UpdateOrderStatusDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, "failed to parse shop data")
+		return
+	}
+
+	order, err := ctrl.service.UpdateOrderStatus(orderID, shop.ID, &dto)
+	if err != nil {
+		transitionErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order status updated successfully", order)
+}
+
+func transitionErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, ErrOrderNotFound):
+		utils.ErrorResponseSimple(c, http.StatusNotFound, "order not found")
+	case errors.Is(err, ErrInvalidTransition):
+		utils.ErrorResponseSimple(c, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
+	repo := NewRepository(db)
+	svc := NewService(repo)
+	ctrl := NewController(svc)
+
+	orders := r.Group("/orders")
+	orders.Use(middlewares.RequireUserAuth(db))
+	{
+		orders.POST("", ctrl.PlaceOrder)
+		orders.GET("", ctrl.GetMyOrders)
+		orders.GET("/:id", ctrl.GetOrder)
+		orders.POST("/:id/cancel", ctrl.CancelOrder)
+	}
+
+	shopOrders := r.Group("/shop/orders")
+	{
+		shopOrders.GET("", middlewares.RequireShopPermission(db, models.PermissionViewOrders), ctrl.GetShopOrders)
+		shopOrders.GET("/:id", middlewares.RequireShopPermission(db, models.PermissionViewOrders), ctrl.GetShopOrder)
+		shopOrders.PUT("/:id/status", middlewares.RequireShopOwnerAuth(db), ctrl.UpdateOrderStatus)
+	}
+}
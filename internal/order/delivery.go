@@ -0,0 +1,38 @@
+package order
+
+import (
+	"errors"
+	"shop-near-u/internal/models"
+	"sort"
+)
+
+var (
+	ErrDeliveryNotSupported    = errors.New("shop does not support delivery")
+	ErrDeliveryAddressRequired = errors.New("delivery address is required for delivery orders")
+	ErrOutsideDeliveryRadius   = errors.New("delivery address is outside the shop's delivery area")
+)
+
+// deliveryFee picks the cheapest tier whose MaxDistance covers the distance.
+// Distances beyond every tier pay the furthest tier's fee, and orders at or
+// above freeAbove (when set) are delivered for free.
+func deliveryFee(tiers []models.DeliveryFeeTier, distance float64, orderTotal float64, freeAbove float64) float64 {
+	if freeAbove > 0 && orderTotal >= freeAbove {
+		return 0
+	}
+	if len(tiers) == 0 {
+		return 0
+	}
+
+	sorted := make([]models.DeliveryFeeTier, len(tiers))
+	copy(sorted, tiers)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MaxDistance < sorted[j].MaxDistance
+	})
+
+	for _, tier := range sorted {
+		if distance <= tier.MaxDistance {
+			return tier.Fee
+		}
+	}
+	return sorted[len(sorted)-1].Fee
+}
@@ -0,0 +1,23 @@
+package order
+
+import (
+	"shop-near-u/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveryFee(t *testing.T) {
+	tiers := []models.DeliveryFeeTier{
+		{MaxDistance: 5000, Fee: 40},
+		{MaxDistance: 2000, Fee: 20},
+	}
+
+	assert.Equal(t, 20.0, deliveryFee(tiers, 1500, 100, 0))
+	assert.Equal(t, 20.0, deliveryFee(tiers, 2000, 100, 0))
+	assert.Equal(t, 40.0, deliveryFee(tiers, 3500, 100, 0))
+	assert.Equal(t, 40.0, deliveryFee(tiers, 8000, 100, 0))
+	assert.Equal(t, 0.0, deliveryFee(tiers, 3500, 500, 500))
+	assert.Equal(t, 40.0, deliveryFee(tiers, 3500, 499.99, 500))
+	assert.Equal(t, 0.0, deliveryFee(nil, 3500, 100, 0))
+}
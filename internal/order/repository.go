@@ -0,0 +1,260 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"shop-near-u/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientStock  = errors.New("insufficient stock")
+	ErrProductUnavailable = errors.New("product is not available")
+	ErrProductNotInShop   = errors.New("product does not belong to this shop")
+)
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{DB: db}
+}
+
+// CreateOrder prices every item against the current ShopProduct row, decrements
+// stock and stores the order in a single transaction. Product rows are locked
+// so concurrent orders cannot oversell.
+func (r *Repository) CreateOrder(order *models.Order) error {
+	return r.CreateOrders([]*models.Order{order})
+}
+
+// CreateOrders stores several orders in one transaction, so either every order
+// is placed or none is.
+func (r *Repository) CreateOrders(orders []*models.Order) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := r.CreateOrdersTx(tx, orders); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// CreateOrdersTx stores the orders using tx and leaves committing or rolling
+// back to the caller.
+func (r *Repository) CreateOrdersTx(tx *gorm.DB, orders []*models.Order) error {
+	for _, order := range orders {
+		if err := createOrder(tx, order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createOrder(tx *gorm.DB, order *models.Order) error {
+	var shop models.Shop
+	if err := tx.First(&shop, order.ShopID).Error; err != nil {
+		return fmt.Errorf("shop with ID %d not found: %w", order.ShopID, err)
+	}
+
+	order.Subtotal = 0
+	order.Total = 0
+	for i := range order.Items {
+		item := &order.Items[i]
+
+		var product models.ShopProduct
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("CatalogProduct").
+			First(&product, item.ShopProductID).Error; err != nil {
+			return fmt.Errorf("product with ID %d not found: %w", item.ShopProductID, err)
+		}
+
+		if product.ShopID != order.ShopID {
+			return fmt.Errorf("%w: product %d", ErrProductNotInShop, product.ID)
+		}
+		if !product.IsAvailable {
+			return fmt.Errorf("%w: product %d", ErrProductUnavailable, product.ID)
+		}
+		if product.Stock < item.Quantity {
+			return fmt.Errorf("%w: product %d has %d left", ErrInsufficientStock, product.ID, product.Stock)
+		}
+
+		if err := tx.Model(&models.ShopProduct{}).Where("id = ?", product.ID).
+			Update("stock", gorm.Expr("stock - ?", item.Quantity)).Error; err != nil {
+			return err
+		}
+
+		item.CatalogID = product.CatalogID
+		item.Name = product.CatalogProduct.Name
+		item.UnitPrice = product.Price
+		item.Discount = product.Discount
+		item.LineTotal = roundPrice(product.EffectivePrice() * float64(item.Quantity))
+
+		order.Subtotal += product.Price * float64(item.Quantity)
+		order.Total += item.LineTotal
+	}
+
+	order.Subtotal = roundPrice(order.Subtotal)
+	order.Total = roundPrice(order.Total)
+	order.Discount = roundPrice(order.Subtotal - order.Total)
+	order.Status = models.OrderStatusPlaced
+
+	if order.FulfilmentType == models.FulfilmentDelivery {
+		if err := applyDelivery(tx, &shop, order); err != nil {
+			return err
+		}
+	}
+
+	return tx.Create(order).Error
+}
+
+// applyDelivery checks the delivery address against the shop's service area
+// polygon when one is set, or else its radius using the same ST_DWithin test
+// as the nearby-shops query, then adds the fee.
+func applyDelivery(tx *gorm.DB, shop *models.Shop, order *models.Order) error {
+	if !shop.SupportsDelivery {
+		return ErrDeliveryNotSupported
+	}
+
+	var check struct {
+		Distance float64
+		Within   bool
+	}
+
+	query := `
+        SELECT
+            ST_Distance(location, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography) AS distance,
+            CASE
+                WHEN service_area IS NOT NULL THEN ST_Covers(service_area, ST_SetSRID(ST_MakePoint(?, ?), 4326))
+                ELSE ST_DWithin(location, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography, ?)
+            END AS within
+        FROM shops
+        WHERE id = ?
+    `
+
+	lon, lat := order.DeliveryLongitude, order.DeliveryLatitude
+	if err := tx.Raw(query, lon, lat, lon, lat, lon, lat, shop.DeliveryRadius, shop.ID).Scan(&check).Error; err != nil {
+		return err
+	}
+
+	if !check.Within {
+		return ErrOutsideDeliveryRadius
+	}
+
+	var tiers []models.DeliveryFeeTier
+	if err := tx.Where("shop_id = ?", shop.ID).Find(&tiers).Error; err != nil {
+		return err
+	}
+
+	order.DeliveryDistance = roundPrice(check.Distance)
+	order.DeliveryFee = deliveryFee(tiers, check.Distance, order.Total, shop.FreeDeliveryAbove)
+	order.Total = roundPrice(order.Total + order.DeliveryFee)
+	return nil
+}
+
+func (r *Repository) GetOrdersByUserID(userID uint) ([]models.Order, error) {
+	var orders []models.Order
+	result := r.DB.Preload("Items").Where("user_id = ?", userID).Order("created_at DESC").Find(&orders)
+	return orders, result.Error
+}
+
+func (r *Repository) GetOrderByID(orderID uint) (*models.Order, error) {
+	var order models.Order
+	if err := r.DB.Preload("Items").First(&order, orderID).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *Repository) GetOrdersByShopID(shopID uint, status string) ([]models.Order, error) {
+	var orders []models.Order
+	query := r.DB.Preload("Items").Where("shop_id = ?", shopID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	result := query.Order("created_at DESC").Find(&orders)
+	return orders, result.Error
+}
+
+// TransitionOrder moves an order to a new status on behalf of a customer
+// (role user, actorID is the user ID) or a shop owner (actorID is the shop ID).
+// Cancelled and rejected orders return their quantities to stock in the same
+// transaction.
+func (r *Repository) TransitionOrder(orderID uint, role string, actorID uint, status string, reason string) (*models.Order, error) {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	var order models.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if (role == models.RoleUser && order.UserID != actorID) ||
+		(role == models.RoleShopOwner && order.ShopID != actorID) {
+		tx.Rollback()
+		return nil, ErrOrderNotFound
+	}
+
+	if !canTransition(role, order.Status, status) {
+		tx.Rollback()
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, order.Status, status)
+	}
+
+	if restocks(status) {
+		var items []models.OrderItem
+		if err := tx.Where("order_id = ?", order.ID).Find(&items).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		for _, item := range items {
+			if err := tx.Model(&models.ShopProduct{}).Where("id = ?", item.ShopProductID).
+				Update("stock", gorm.Expr("stock + ?", item.Quantity)).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+	}
+
+	applyStatus(&order, status, time.Now())
+	if reason != "" {
+		order.Reason = reason
+	}
+
+	if err := tx.Save(&order).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return r.GetOrderByID(order.ID)
+}
+
+func roundPrice(value float64) float64 {
+	return math.Round(value*100) / 100
+}
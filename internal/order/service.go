@@ -0,0 +1,153 @@
+package order
+
+import (
+	"errors"
+	"shop-near-u/internal/models"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+var ErrOrderNotFound = errors.New("order not found")
+
+type Service struct {
+	repository *Repository
+}
+
+func NewService(r *Repository) *Service {
+	return &Service{repository: r}
+}
+
+func (s *Service) PlaceOrder(dto *PlaceOrderDTORequest, userID uint) (*models.Order, error) {
+	order, err := newOrder(dto, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.CreateOrder(order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// PlaceOrders places one order per request for the same user, all or nothing.
+func (s *Service) PlaceOrders(requests []PlaceOrderDTORequest, userID uint) ([]*models.Order, error) {
+	orders, err := newOrders(requests, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.CreateOrders(orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// PlaceOrdersTx is PlaceOrders inside a transaction the caller owns, so the
+// orders commit or roll back together with the caller's own writes.
+func (s *Service) PlaceOrdersTx(tx *gorm.DB, requests []PlaceOrderDTORequest, userID uint) ([]*models.Order, error) {
+	orders, err := newOrders(requests, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.CreateOrdersTx(tx, orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func newOrders(requests []PlaceOrderDTORequest, userID uint) ([]*models.Order, error) {
+	orders := make([]*models.Order, 0, len(requests))
+	for i := range requests {
+		order, err := newOrder(&requests[i], userID)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+func (s *Service) GetUserOrders(userID uint) ([]models.Order, error) {
+	return s.repository.GetOrdersByUserID(userID)
+}
+
+// GetUserOrder returns the order only when it belongs to the given user.
+func (s *Service) GetUserOrder(orderID uint, userID uint) (*models.Order, error) {
+	order, err := s.repository.GetOrderByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (s *Service) CancelOrder(orderID uint, userID uint, reason string) (*models.Order, error) {
+	return s.repository.TransitionOrder(orderID, models.RoleUser, userID, models.OrderStatusCancelled, reason)
+}
+
+func (s *Service) GetShopOrders(shopID uint, status string) ([]models.Order, error) {
+	return s.repository.GetOrdersByShopID(shopID, status)
+}
+
+// GetShopOrder returns the order only when it was placed with the given shop.
+func (s *Service) GetShopOrder(orderID uint, shopID uint) (*models.Order, error) {
+	order, err := s.repository.GetOrderByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.ShopID != shopID {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (s *Service) UpdateOrderStatus(orderID uint, shopID uint, dto *UpdateOrderStatusDTORequest) (*models.Order, error) {
+	return s.repository.TransitionOrder(orderID, models.RoleShopOwner, shopID, dto.Status, dto.Reason)
+}
+
+func newOrder(dto *PlaceOrderDTORequest, userID uint) (*models.Order, error) {
+	order := &models.Order{
+		UserID:         userID,
+		ShopID:         dto.ShopID,
+		Items:          mergeItems(dto.Items),
+		FulfilmentType: models.FulfilmentPickup,
+	}
+
+	if dto.FulfilmentType == models.FulfilmentDelivery {
+		if dto.DeliveryAddress == nil {
+			return nil, ErrDeliveryAddressRequired
+		}
+		order.FulfilmentType = models.FulfilmentDelivery
+		order.DeliveryAddress = dto.DeliveryAddress.Address
+		order.DeliveryLatitude = *dto.DeliveryAddress.Latitude
+		order.DeliveryLongitude = *dto.DeliveryAddress.Longitude
+	}
+
+	return order, nil
+}
+
+// mergeItems folds repeated products into a single line and orders the lines
+// by product ID so row locks are always taken in the same order.
+func mergeItems(items []PlaceOrderItemDTO) []models.OrderItem {
+	quantities := make(map[uint]int)
+	for _, item := range items {
+		quantities[item.ShopProductID] += item.Quantity
+	}
+
+	merged := make([]models.OrderItem, 0, len(quantities))
+	for productID, quantity := range quantities {
+		merged = append(merged, models.OrderItem{
+			ShopProductID: productID,
+			Quantity:      quantity,
+		})
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].ShopProductID < merged[j].ShopProductID
+	})
+	return merged
+}
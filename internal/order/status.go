@@ -0,0 +1,57 @@
+package order
+
+import (
+	"errors"
+	"shop-near-u/internal/models"
+	"time"
+)
+
+var ErrInvalidTransition = errors.New("invalid order status transition")
+
+// transitions lists, for every status, the statuses an order may move to next.
+var transitions = map[string][]string{
+	models.OrderStatusPlaced:   {models.OrderStatusAccepted, models.OrderStatusRejected, models.OrderStatusCancelled},
+	models.OrderStatusAccepted: {models.OrderStatusReady, models.OrderStatusCancelled},
+	models.OrderStatusReady:    {models.OrderStatusCompleted, models.OrderStatusCancelled},
+}
+
+// canTransition reports whether the given role may move an order from one
+// status to another. Customers may only cancel orders that are still placed.
+func canTransition(role string, from string, to string) bool {
+	if role == models.RoleUser {
+		return from == models.OrderStatusPlaced && to == models.OrderStatusCancelled
+	}
+	if role != models.RoleShopOwner {
+		return false
+	}
+
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// restocks reports whether moving into the status returns the ordered
+// quantities to the shop's stock.
+func restocks(status string) bool {
+	return status == models.OrderStatusCancelled || status == models.OrderStatusRejected
+}
+
+// applyStatus sets the new status and stamps the matching timestamp column.
+func applyStatus(order *models.Order, status string, at time.Time) {
+	order.Status = status
+	switch status {
+	case models.OrderStatusAccepted:
+		order.AcceptedAt = &at
+	case models.OrderStatusReady:
+		order.ReadyAt = &at
+	case models.OrderStatusCompleted:
+		order.CompletedAt = &at
+	case models.OrderStatusRejected:
+		order.RejectedAt = &at
+	case models.OrderStatusCancelled:
+		order.CancelledAt = &at
+	}
+}
@@ -0,0 +1,44 @@
+package order
+
+import (
+	"shop-near-u/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransitionShopOwner(t *testing.T) {
+	owner := models.RoleShopOwner
+
+	assert.True(t, canTransition(owner, models.OrderStatusPlaced, models.OrderStatusAccepted))
+	assert.True(t, canTransition(owner, models.OrderStatusPlaced, models.OrderStatusRejected))
+	assert.True(t, canTransition(owner, models.OrderStatusAccepted, models.OrderStatusReady))
+	assert.True(t, canTransition(owner, models.OrderStatusReady, models.OrderStatusCompleted))
+	assert.True(t, canTransition(owner, models.OrderStatusReady, models.OrderStatusCancelled))
+
+	assert.False(t, canTransition(owner, models.OrderStatusPlaced, models.OrderStatusCompleted))
+	assert.False(t, canTransition(owner, models.OrderStatusAccepted, models.OrderStatusRejected))
+	assert.False(t, canTransition(owner, models.OrderStatusCompleted, models.OrderStatusCancelled))
+	assert.False(t, canTransition(owner, models.OrderStatusCancelled, models.OrderStatusAccepted))
+}
+
+func TestCanTransitionCustomer(t *testing.T) {
+	user := models.RoleUser
+
+	assert.True(t, canTransition(user, models.OrderStatusPlaced, models.OrderStatusCancelled))
+	assert.False(t, canTransition(user, models.OrderStatusAccepted, models.OrderStatusCancelled))
+	assert.False(t, canTransition(user, models.OrderStatusPlaced, models.OrderStatusAccepted))
+}
+
+func TestApplyStatusStampsTimestamp(t *testing.T) {
+	order := &models.Order{Status: models.OrderStatusPlaced}
+	now := time.Now()
+
+	applyStatus(order, models.OrderStatusAccepted, now)
+
+	assert.Equal(t, models.OrderStatusAccepted, order.Status)
+	assert.NotNil(t, order.AcceptedAt)
+	assert.Equal(t, now, *order.AcceptedAt)
+	assert.Nil(t, order.CancelledAt)
+}
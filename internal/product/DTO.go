@@ -1,7 +1,10 @@
 package product
 
+// AddProductDTORequest names the catalog product either by ID or by a
+// barcode scanned off the shelf.
 type AddProductDTORequest struct {
-	CatalogID   uint    `json:"catalog_id" binding:"required"`
+	CatalogID   uint    `json:"catalog_id" binding:"required_without=Barcode"`
+	Barcode     string  `json:"barcode" binding:"required_without=CatalogID,max=20"`
 	Price       float64 `json:"price" binding:"required,gt=0"`
 	Stock       int     `json:"stock" binding:"required,gte=0"`
 	Discount    float64 `json:"discount" binding:"gte=0"`
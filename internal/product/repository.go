@@ -1,12 +1,19 @@
 package product
 
 import (
+	"errors"
 	"fmt"
 	"shop-near-u/internal/models"
 
 	"gorm.io/gorm"
 )
 
+var (
+	ErrCatalogProductUnavailable = errors.New("catalog product is awaiting approval, was rejected or has been retired")
+	ErrBarcodeNotFound           = errors.New("no catalog product has this barcode")
+	ErrBarcodeMismatch           = errors.New("barcode belongs to a different catalog product")
+)
+
 type Repository struct {
 	DB *gorm.DB
 }
@@ -15,19 +22,74 @@ func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{DB: db}
 }
 
-func (r *Repository) AddProduct(product *models.ShopProduct) error {
+// FindCatalogIDByBarcode returns the catalog product carrying a normalised
+// GTIN.
+func (r *Repository) FindCatalogIDByBarcode(gtin string) (uint, error) {
+	var barcode models.CatalogBarcode
+	if err := r.DB.Where("gtin = ?", gtin).First(&barcode).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrBarcodeNotFound
+		}
+		return 0, err
+	}
+	return barcode.CatalogProductID, nil
+}
+
+// AddProduct lists a catalog product in a shop and records who added it.
+func (r *Repository) AddProduct(product *models.ShopProduct, staffID *uint) error {
 	// First verify that both Shop and CatalogProduct exist
 	var shop models.Shop
 	if err := r.DB.First(&shop, product.ShopID).Error; err != nil {
 		return fmt.Errorf("shop with ID %d not found: %w", product.ShopID, err)
 	}
 
+	// Follow the redirect if the catalog product was merged into another one
+	var redirect models.CatalogRedirect
+	if err := r.DB.Where("from_id = ?", product.CatalogID).Limit(1).Find(&redirect).Error; err != nil {
+		return err
+	}
+	if redirect.ToID != 0 {
+		product.CatalogID = redirect.ToID
+	}
+
 	var catalogProduct models.CatalogProduct
 	if err := r.DB.First(&catalogProduct, product.CatalogID).Error; err != nil {
 		return fmt.Errorf("catalog product with ID %d not found: %w", product.CatalogID, err)
 	}
+	if !catalogProduct.UsableBy(product.ShopID) {
+		return ErrCatalogProductUnavailable
+	}
+
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
 
-	return r.DB.Create(product).Error
+	if err := tx.Create(product).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	change := &models.InventoryChange{
+		ShopID:        product.ShopID,
+		ShopProductID: product.ID,
+		StaffID:       staffID,
+		Action:        models.InventoryActionCreated,
+		StockAfter:    product.Stock,
+		PriceAfter:    product.Price,
+	}
+	if err := tx.Create(change).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
 }
 
 func (r *Repository) GetProductsByShopID(shopID uint) ([]models.ShopProduct, error) {
@@ -42,21 +104,91 @@ func (r *Repository) GetProductByID(productID uint) (*models.ShopProduct, error)
 	return &product, result.Error
 }
 
-func (r *Repository) UpdateProduct(product *models.ShopProduct) error {
-	// First verify that both Shop and CatalogProduct exist
-	var shop models.Shop
-	if err := r.DB.First(&shop, product.ShopID).Error; err != nil {
-		return fmt.Errorf("shop with ID %d not found: %w", product.ShopID, err)
+func (r *Repository) GetShopProduct(productID uint, shopID uint) (*models.ShopProduct, error) {
+	var product models.ShopProduct
+	if err := r.DB.Where("id = ? AND shop_id = ?", productID, shopID).First(&product).Error; err != nil {
+		return nil, err
 	}
+	return &product, nil
+}
 
-	var catalogProduct models.CatalogProduct
-	if err := r.DB.First(&catalogProduct, product.CatalogID).Error; err != nil {
-		return fmt.Errorf("catalog product with ID %d not found: %w", product.CatalogID, err)
+// UpdateProduct saves the product together with the change record describing it.
+func (r *Repository) UpdateProduct(product *models.ShopProduct, change *models.InventoryChange) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Model(product).Select("price", "stock", "discount", "is_available").Updates(product).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Create(change).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// DeleteProduct removes the listing together with the cart lines and
+// reservations that reference it, and logs the removal.
+func (r *Repository) DeleteProduct(product *models.ShopProduct, staffID *uint) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Where("shop_product_id = ?", product.ID).Delete(&models.CartItem{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("shop_product_id = ?", product.ID).Delete(&models.StockReservation{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(&models.ShopProduct{}, product.ID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	change := &models.InventoryChange{
+		ShopID:        product.ShopID,
+		ShopProductID: product.ID,
+		StaffID:       staffID,
+		Action:        models.InventoryActionDeleted,
+		StockBefore:   product.Stock,
+		PriceBefore:   product.Price,
+	}
+	if err := tx.Create(change).Error; err != nil {
+		tx.Rollback()
+		return err
 	}
 
-	return r.DB.Save(product).Error
+	return tx.Commit().Error
 }
 
-func (r *Repository) DeleteProduct(productID uint) error {
-	return r.DB.Delete(&models.ShopProduct{}, productID).Error
+func (r *Repository) GetInventoryChanges(shopID uint, limit int) ([]models.InventoryChange, error) {
+	var changes []models.InventoryChange
+	err := r.DB.Preload("Staff").
+		Where("shop_id = ?", shopID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&changes).Error
+	return changes, err
 }
@@ -1,6 +1,12 @@
 package product
 
-import "shop-near-u/internal/models"
+import (
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/utils"
+)
+
+// maxInventoryChanges caps how much of the change log one request returns.
+const maxInventoryChanges = 200
 
 type Service struct {
 	repository *Repository
@@ -10,17 +16,35 @@ func NewService(r *Repository) *Service {
 	return &Service{repository: r}
 }
 
-func (s *Service) AddProduct(dto *AddProductDTORequest, shopID uint) error {
+// AddProduct lists a catalog product in the shop. staffID is the staff member
+// making the change, or nil for the owner.
+func (s *Service) AddProduct(dto *AddProductDTORequest, shopID uint, staffID *uint) error {
+	catalogID := dto.CatalogID
+	if dto.Barcode != "" {
+		gtin, err := utils.NormalizeGTIN(dto.Barcode)
+		if err != nil {
+			return err
+		}
+		barcodeCatalogID, err := s.repository.FindCatalogIDByBarcode(gtin)
+		if err != nil {
+			return err
+		}
+		if catalogID != 0 && catalogID != barcodeCatalogID {
+			return ErrBarcodeMismatch
+		}
+		catalogID = barcodeCatalogID
+	}
+
 	product := &models.ShopProduct{
 		ShopID:      shopID,
-		CatalogID:   dto.CatalogID,
+		CatalogID:   catalogID,
 		Price:       dto.Price,
 		Stock:       dto.Stock,
 		Discount:    dto.Discount,
 		IsAvailable: dto.IsAvailable,
 	}
 
-	return s.repository.AddProduct(product)
+	return s.repository.AddProduct(product, staffID)
 }
 
 func (s *Service) GetProductsByShopID(shopID uint) ([]models.ShopProduct, error) {
@@ -31,17 +55,46 @@ func (s *Service) GetProductByID(productID uint) (*models.ShopProduct, error) {
 	return s.repository.GetProductByID(productID)
 }
 
-func (s *Service) UpdateProduct(productDTO *ProductUpdateDTORequest) error {
-	product := &models.ShopProduct{
-		ID:          productDTO.ID,
-		Price:       productDTO.Price,
-		Stock:       productDTO.Stock,
-		Discount:    productDTO.Discount,
-		IsAvailable: productDTO.IsAvailable,
+// UpdateProduct changes one of the shop's listings. The price is kept when
+// none is given.
+func (s *Service) UpdateProduct(productDTO *ProductUpdateDTORequest, shopID uint, staffID *uint) error {
+	product, err := s.repository.GetShopProduct(productDTO.ID, shopID)
+	if err != nil {
+		return err
 	}
-	return s.repository.UpdateProduct(product)
+
+	change := &models.InventoryChange{
+		ShopID:        shopID,
+		ShopProductID: product.ID,
+		StaffID:       staffID,
+		Action:        models.InventoryActionUpdated,
+		StockBefore:   product.Stock,
+		PriceBefore:   product.Price,
+	}
+
+	if productDTO.Price > 0 {
+		product.Price = productDTO.Price
+	}
+	product.Stock = productDTO.Stock
+	product.Discount = productDTO.Discount
+	product.IsAvailable = productDTO.IsAvailable
+
+	change.StockAfter = product.Stock
+	change.PriceAfter = product.Price
+	return s.repository.UpdateProduct(product, change)
+}
+
+func (s *Service) DeleteProduct(productID uint, shopID uint, staffID *uint) error {
+	product, err := s.repository.GetShopProduct(productID, shopID)
+	if err != nil {
+		return err
+	}
+	return s.repository.DeleteProduct(product, staffID)
 }
 
-func (s *Service) DeleteProduct(productID uint) error {
-	return s.repository.DeleteProduct(productID)
+func (s *Service) GetInventoryChanges(shopID uint, limit int) ([]models.InventoryChange, error) {
+	if limit <= 0 || limit > maxInventoryChanges {
+		limit = maxInventoryChanges
+	}
+	return s.repository.GetInventoryChanges(shopID, limit)
 }
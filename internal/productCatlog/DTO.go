@@ -1,9 +1,89 @@
 package productcatlog
 
+import (
+	"shop-near-u/internal/models"
+	"time"
+)
+
 type CreateCatalogProductDTO struct {
 	Name        string `json:"name" binding:"required"`
 	Brand       string `json:"brand"`
-	Category    string `json:"category" binding:"required"`
+	Category    string `json:"category" binding:"required_without=CategoryID"`
+	CategoryID  *uint  `json:"category_id"`
 	Description string `json:"description" binding:"required"`
 	ImageURL    string `json:"image_url"`
+	ImageID     *uint  `json:"image_id"`
+
+	// Barcodes are EAN-8, UPC-A, EAN-13 or GTIN-14 codes printed on the product.
+	Barcodes []string `json:"barcodes" binding:"omitempty,max=10,dive,max=20"`
+}
+
+type AddBarcodeDTORequest struct {
+	Code     string `json:"code" binding:"required,max=20"`
+	PackSize string `json:"pack_size" binding:"max=50"`
+}
+
+// UpdateCatalogProductDTO changes only the fields that are present.
+// CategoryID takes precedence over the free-text Category.
+type UpdateCatalogProductDTO struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Brand       *string `json:"brand" binding:"omitempty,max=100"`
+	Category    *string `json:"category" binding:"omitempty,min=1,max=100"`
+	CategoryID  *uint   `json:"category_id"`
+	Description *string `json:"description"`
+	ImageURL    *string `json:"image_url" binding:"omitempty,max=255"`
+	ImageID     *uint   `json:"image_id"`
+}
+
+type FieldChangeDTO struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type CatalogRevisionDTOResponse struct {
+	ID             uint                      `json:"id"`
+	EditedByUserID *uint                     `json:"edited_by_user_id,omitempty"`
+	EditedByShopID *uint                     `json:"edited_by_shop_id,omitempty"`
+	Changes        map[string]FieldChangeDTO `json:"changes"`
+	CreatedAt      time.Time                 `json:"created_at"`
+}
+
+type RetireCatalogProductDTOResponse struct {
+	Product         *models.CatalogProduct `json:"product"`
+	ListingsRemoved int64                  `json:"listings_removed"`
+}
+
+type MergeCatalogProductDTORequest struct {
+	IntoID uint `json:"into_id" binding:"required"`
+}
+
+type MergeCatalogProductDTOResponse struct {
+	Product       *models.CatalogProduct `json:"product"`
+	MergedID      uint                   `json:"merged_id"`
+	ListingsMoved int64                  `json:"listings_moved"`
+}
+
+// DuplicateGroupDTOResponse is a set of catalog products that normalise to
+// the same name, brand and category.
+type DuplicateGroupDTOResponse struct {
+	MatchKey string                  `json:"match_key"`
+	Products []models.CatalogProduct `json:"products"`
+}
+
+type NearbyProductDTOResponse struct {
+	ShopProductID  uint    `json:"shop_product_id"`
+	CatalogID      uint    `json:"catalog_id"`
+	ProductName    string  `json:"product_name"`
+	Brand          string  `json:"brand"`
+	ShopID         uint    `json:"shop_id"`
+	ShopName       string  `json:"shop_name"`
+	Address        string  `json:"address"`
+	Latitude       float64 `json:"latitude"`
+	Longitude      float64 `json:"longitude"`
+	IsOpen         bool    `json:"is_open"`
+	Price          float64 `json:"price"`
+	Discount       float64 `json:"discount"`
+	EffectivePrice float64 `json:"effective_price"`
+	Stock          int     `json:"stock"`
+	Distance       float64 `json:"distance"`
 }
@@ -1,7 +1,10 @@
 package productcatlog
 
 import (
+	"errors"
 	"net/http"
+	"shop-near-u/internal/middlewares"
+	"shop-near-u/internal/models"
 	"shop-near-u/internal/utils"
 	"strconv"
 
@@ -18,6 +21,12 @@ func NewController(s *Service) *Controller {
 }
 
 func (ctrl *Controller) CreateCatalogProduct(c *gin.Context) {
+	shop, ok := shopFromContext(c)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	var dto CreateCatalogProductDTO
 
 	if err := c.ShouldBindJSON(&dto); err != nil {
@@ -25,16 +34,230 @@ func (ctrl *Controller) CreateCatalogProduct(c *gin.Context) {
 		return
 	}
 
-	err := ctrl.service.CreateCatalogProduct(&dto)
+	product, err := ctrl.service.SubmitCatalogProduct(&dto, shop.ID)
+	if err != nil {
+		catalogErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Catalog product submitted for review", product)
+}
+
+func (ctrl *Controller) GetSubmissions(c *gin.Context) {
+	shop, ok := shopFromContext(c)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	products, err := ctrl.service.GetSubmissions(shop.ID)
 	if err != nil {
 		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusCreated, "Catalog product created successfully", gin.H{
-		"name": dto.Name,
-	})
+	utils.SuccessResponse(c, http.StatusOK, "Catalog submissions retrieved successfully", products)
+}
+
+func (ctrl *Controller) GetCatalogProduct(c *gin.Context) {
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	var shopID uint
+	if shop, ok := shopFromContext(c); ok {
+		shopID = shop.ID
+	}
+
+	product, err := ctrl.service.GetVisibleCatalogProduct(productID, shopID)
+	if err != nil {
+		catalogErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Catalog product retrieved successfully", product)
+}
+
+func (ctrl *Controller) GetRevisions(c *gin.Context) {
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	var shopID uint
+	if shop, ok := shopFromContext(c); ok {
+		shopID = shop.ID
+	}
+
+	product, err := ctrl.service.GetVisibleCatalogProduct(productID, shopID)
+	if err != nil {
+		catalogErrorResponse(c, err)
+		return
+	}
+
+	revisions, err := ctrl.service.GetRevisions(product.ID)
+	if err != nil {
+		catalogErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Catalog product revisions retrieved successfully", revisions)
+}
 
+// UpdateCatalogProduct lets a shop correct its own submission while it is
+// still pending review.
+func (ctrl *Controller) UpdateCatalogProduct(c *gin.Context) {
+	shop, ok := shopFromContext(c)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	var dto UpdateCatalogProductDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	product, err := ctrl.service.UpdateCatalogProduct(productID, &dto, Editor{ShopID: shop.ID})
+	if err != nil {
+		catalogErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Catalog product updated successfully", product)
+}
+
+// RetireCatalogProduct withdraws a shop's own pending submission. Pass
+// cascade=true to also remove the shop's listings of it.
+func (ctrl *Controller) RetireCatalogProduct(c *gin.Context) {
+	shop, ok := shopFromContext(c)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	cascade, err := strconv.ParseBool(c.DefaultQuery("cascade", "false"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid cascade")
+		return
+	}
+
+	result, err := ctrl.service.RetireCatalogProduct(productID, cascade, Editor{ShopID: shop.ID})
+	if err != nil {
+		catalogErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Catalog product retired successfully", result)
+}
+
+// LookupBarcode returns the product behind a scanned barcode.
+func (ctrl *Controller) LookupBarcode(c *gin.Context) {
+	var shopID uint
+	if shop, ok := shopFromContext(c); ok {
+		shopID = shop.ID
+	}
+
+	product, err := ctrl.service.LookupBarcode(c.Param("code"), shopID)
+	if err != nil {
+		catalogErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Catalog product retrieved successfully", product)
+}
+
+func (ctrl *Controller) AddBarcode(c *gin.Context) {
+	shop, ok := shopFromContext(c)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	var dto AddBarcodeDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	barcode, err := ctrl.service.AddBarcode(productID, &dto, Editor{ShopID: shop.ID})
+	if err != nil {
+		catalogErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Barcode added successfully", barcode)
+}
+
+func (ctrl *Controller) RemoveBarcode(c *gin.Context) {
+	shop, ok := shopFromContext(c)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog product ID")
+		return
+	}
+
+	barcodeID, err := utils.ParseUintParam(c.Param("barcodeId"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid barcode ID")
+		return
+	}
+
+	if err := ctrl.service.RemoveBarcode(productID, barcodeID, Editor{ShopID: shop.ID}); err != nil {
+		catalogErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Barcode removed successfully", nil)
+}
+
+// FindDuplicates lets a shop check for existing products before submitting.
+func (ctrl *Controller) FindDuplicates(c *gin.Context) {
+	name := c.Query("name")
+	if name == "" {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	var shopID uint
+	if shop, ok := shopFromContext(c); ok {
+		shopID = shop.ID
+	}
+
+	products, err := ctrl.service.FindDuplicateCandidates(name, c.Query("brand"), c.Query("category"), shopID)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Duplicate candidates retrieved successfully", products)
 }
 
 func (ctrl *Controller) SuggestCatalogProducts(c *gin.Context) {
@@ -45,7 +268,12 @@ func (ctrl *Controller) SuggestCatalogProducts(c *gin.Context) {
 		limit = 10
 	}
 
-	products, err := ctrl.service.SuggestCatalogProducts(keyword, limit)
+	var shopID uint
+	if shop, ok := shopFromContext(c); ok {
+		shopID = shop.ID
+	}
+
+	products, err := ctrl.service.SuggestCatalogProducts(keyword, c.Query("category"), limit, shopID)
 	if err != nil {
 		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
 		return
@@ -56,13 +284,107 @@ func (ctrl *Controller) SuggestCatalogProducts(c *gin.Context) {
 	})
 }
 
+func (ctrl *Controller) FindNearbyAvailability(c *gin.Context) {
+	var catalogID uint
+	if catalogIDParam := c.Query("catalog_id"); catalogIDParam != "" {
+		id, err := utils.ParseUintParam(catalogIDParam)
+		if err != nil {
+			utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid catalog ID")
+			return
+		}
+		catalogID = id
+	}
+
+	lat, err := utils.ParseFloatParam(c.Query("lat"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid latitude")
+		return
+	}
+
+	lon, err := utils.ParseFloatParam(c.Query("lon"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid longitude")
+		return
+	}
+
+	radius, err := utils.ParseFloatParam(c.DefaultQuery("radius", "5000"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid radius")
+		return
+	}
+
+	limit, err := utils.ParseIntParam(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	sortBy := c.DefaultQuery("sort", "distance")
+	if sortBy != "distance" && sortBy != "price" {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid sort, use distance or price")
+		return
+	}
+
+	products, err := ctrl.service.FindNearbyAvailability(catalogID, c.Query("keyword"), lat, lon, radius, sortBy, limit)
+	if err != nil {
+		if errors.Is(err, ErrProductQueryRequired) {
+			utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Nearby availability retrieved successfully", products)
+}
+
+func catalogErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrCatalogProductNotFound),
+		errors.Is(err, ErrBarcodeNotFound):
+		utils.ErrorResponseSimple(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrCatalogImageNotFound),
+		errors.Is(err, ErrCategoryNotFound),
+		errors.Is(err, utils.ErrInvalidBarcode):
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrCatalogProductNotEditable):
+		utils.ErrorResponseSimple(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, ErrCatalogProductInUse),
+		errors.Is(err, ErrCatalogProductRetired),
+		errors.Is(err, ErrDuplicateCatalogProduct),
+		errors.Is(err, ErrBarcodeTaken):
+		utils.ErrorResponseSimple(c, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func shopFromContext(c *gin.Context) (models.Shop, bool) {
+	shopData, exists := c.Get("shop")
+	if !exists {
+		return models.Shop{}, false
+	}
+	shop, ok := shopData.(models.Shop)
+	return shop, ok
+}
+
 func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
 	repo := NewRepository(db)
 	svc := NewService(repo)
 	ctrl := NewController(svc)
 	productCatlogGroup := r.Group("/api/catalog-products")
 	{
-		productCatlogGroup.POST("/", ctrl.CreateCatalogProduct)
-		productCatlogGroup.GET("/suggest", ctrl.SuggestCatalogProducts)
+		productCatlogGroup.POST("/", middlewares.RequireShopPermission(db, models.PermissionManageProducts), ctrl.CreateCatalogProduct)
+		productCatlogGroup.GET("/mine", middlewares.RequireShopPermission(db, models.PermissionManageProducts), ctrl.GetSubmissions)
+		productCatlogGroup.GET("/suggest", middlewares.OptionalShopAuth(db), ctrl.SuggestCatalogProducts)
+		productCatlogGroup.GET("/duplicates", middlewares.OptionalShopAuth(db), ctrl.FindDuplicates)
+		productCatlogGroup.GET("/barcode/:code", middlewares.OptionalShopAuth(db), ctrl.LookupBarcode)
+		productCatlogGroup.GET("/:id", middlewares.OptionalShopAuth(db), ctrl.GetCatalogProduct)
+		productCatlogGroup.GET("/:id/revisions", middlewares.OptionalShopAuth(db), ctrl.GetRevisions)
+		productCatlogGroup.PUT("/:id", middlewares.RequireShopPermission(db, models.PermissionManageProducts), ctrl.UpdateCatalogProduct)
+		productCatlogGroup.DELETE("/:id", middlewares.RequireShopPermission(db, models.PermissionManageProducts), ctrl.RetireCatalogProduct)
+		productCatlogGroup.POST("/:id/barcodes", middlewares.RequireShopPermission(db, models.PermissionManageProducts), ctrl.AddBarcode)
+		productCatlogGroup.DELETE("/:id/barcodes/:barcodeId", middlewares.RequireShopPermission(db, models.PermissionManageProducts), ctrl.RemoveBarcode)
+		productCatlogGroup.GET("/nearby", ctrl.FindNearbyAvailability)
 	}
 }
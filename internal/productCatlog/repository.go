@@ -1,10 +1,13 @@
 package productcatlog
 
 import (
+	"fmt"
 	"shop-near-u/internal/models"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository struct {
@@ -19,17 +22,466 @@ func (r *Repository) CreateCatalogProduct(product *models.CatalogProduct) error
 	return r.DB.Create(product).Error
 }
 
-func (r *Repository) Suggest(keyword string, limit int) (*[]models.CatalogProduct, error) {
+func (r *Repository) GetByID(id uint) (*models.CatalogProduct, error) {
+	var product models.CatalogProduct
+	if err := r.DB.Preload("Barcodes", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).First(&product, id).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// UpdateCatalogProduct saves the editable fields and the revision describing
+// the edit in one transaction.
+func (r *Repository) UpdateCatalogProduct(product *models.CatalogProduct, revision *models.CatalogProductRevision) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Model(product).Select("name", "brand", "category", "category_id", "description", "image_url", "image_id", "match_key").Omit(clause.Associations).Updates(product).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Create(revision).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (r *Repository) GetCategory(id uint) (*models.Category, error) {
+	var category models.Category
+	if err := r.DB.First(&category, id).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *Repository) FindCategoryBySlug(slug string) (*models.Category, error) {
+	var category models.Category
+	if err := r.DB.Where("slug = ?", slug).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// FindBarcode returns the barcode row for a normalised GTIN.
+func (r *Repository) FindBarcode(gtin string) (*models.CatalogBarcode, error) {
+	var barcode models.CatalogBarcode
+	if err := r.DB.Where("gtin = ?", gtin).First(&barcode).Error; err != nil {
+		return nil, err
+	}
+	return &barcode, nil
+}
+
+func (r *Repository) AddBarcode(barcode *models.CatalogBarcode) error {
+	return r.DB.Create(barcode).Error
+}
+
+func (r *Repository) DeleteBarcode(id uint, catalogID uint) (bool, error) {
+	result := r.DB.Where("id = ? AND catalog_product_id = ?", id, catalogID).Delete(&models.CatalogBarcode{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ImageUsableBy reports whether the image exists and, when shopID is not 0,
+// was uploaded by that shop.
+func (r *Repository) ImageUsableBy(imageID uint, shopID uint) (bool, error) {
+	query := r.DB.Model(&models.Image{}).Where("id = ?", imageID)
+	if shopID != 0 {
+		query = query.Where("uploaded_by_shop_id = ?", shopID)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+func (r *Repository) GetRevisions(catalogID uint) ([]models.CatalogProductRevision, error) {
+	var revisions []models.CatalogProductRevision
+	err := r.DB.Where("catalog_product_id = ?", catalogID).Order("created_at DESC, id DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *Repository) GetSubmissions(shopID uint) ([]models.CatalogProduct, error) {
+	var products []models.CatalogProduct
+	err := r.DB.Where("submitted_by_shop_id = ?", shopID).Order("created_at DESC").Find(&products).Error
+	return products, err
+}
+
+func (r *Repository) ListByStatus(status string, page int, limit int) ([]models.CatalogProduct, int64, error) {
+	query := r.DB.Model(&models.CatalogProduct{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var products []models.CatalogProduct
+	err := query.Order("created_at ASC, id ASC").Offset((page - 1) * limit).Limit(limit).Find(&products).Error
+	return products, total, err
+}
+
+// Review records an admin decision on a pending product. A rejected product
+// gives up its barcodes so they can go to the real product. It returns false
+// when the product was no longer pending.
+func (r *Repository) Review(id uint, status string, reviewerID uint, reason string, at time.Time) (bool, error) {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	result := tx.Model(&models.CatalogProduct{}).
+		Where("id = ? AND status = ?", id, models.CatalogStatusPending).
+		Updates(map[string]interface{}{
+			"status":           status,
+			"reviewed_by_id":   reviewerID,
+			"reviewed_at":      at,
+			"rejection_reason": reason,
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return false, nil
+	}
+
+	if status == models.CatalogStatusRejected {
+		if err := tx.Where("catalog_product_id = ?", id).Delete(&models.CatalogBarcode{}).Error; err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	return true, tx.Commit().Error
+}
+
+// RetireCatalogProduct marks the product retired and removes any shop
+// listings of it in the same transaction: reservations and cart lines on
+// those listings are dropped and each removal is written to the shop's
+// inventory log. It returns how many listings were removed.
+func (r *Repository) RetireCatalogProduct(id uint, at time.Time) (int64, error) {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+
+	var listings []models.ShopProduct
+	if err := tx.Where("catalog_id = ?", id).Find(&listings).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if len(listings) > 0 {
+		listingIDs := make([]uint, 0, len(listings))
+		changes := make([]models.InventoryChange, 0, len(listings))
+		for _, listing := range listings {
+			listingIDs = append(listingIDs, listing.ID)
+			changes = append(changes, models.InventoryChange{
+				ShopID:        listing.ShopID,
+				ShopProductID: listing.ID,
+				Action:        models.InventoryActionDeleted,
+				StockBefore:   listing.Stock,
+				PriceBefore:   listing.Price,
+			})
+		}
+
+		// Finished holds still reference the listings too, so all of them go.
+		if err := tx.Where("shop_product_id IN ?", listingIDs).Delete(&models.StockReservation{}).Error; err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to remove reservations: %w", err)
+		}
+
+		if err := tx.Where("shop_product_id IN ?", listingIDs).Delete(&models.CartItem{}).Error; err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to clear cart items: %w", err)
+		}
+
+		if err := tx.Create(&changes).Error; err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to log inventory changes: %w", err)
+		}
+
+		if err := tx.Delete(&models.ShopProduct{}, listingIDs).Error; err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to remove shop products: %w", err)
+		}
+	}
+
+	if err := tx.Model(&models.CatalogProduct{}).Where("id = ?", id).Update("retired_at", at).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return int64(len(listings)), tx.Commit().Error
+}
+
+// CountShopProducts returns how many shop listings point at the catalog product.
+func (r *Repository) CountShopProducts(catalogID uint) (int64, error) {
+	var count int64
+	err := r.DB.Model(&models.ShopProduct{}).Where("catalog_id = ?", catalogID).Count(&count).Error
+	return count, err
+}
+
+// FindByMatchKey lists products sharing the duplicate-detection key that
+// shopID can see: approved ones and the shop's own pending submissions.
+func (r *Repository) FindByMatchKey(matchKey string, shopID uint) ([]models.CatalogProduct, error) {
+	var products []models.CatalogProduct
+	err := r.DB.Where("match_key = ? AND retired_at IS NULL", matchKey).
+		Where("(status = ? OR (status = ? AND submitted_by_shop_id = ?))",
+			models.CatalogStatusApproved, models.CatalogStatusPending, shopID).
+		Order("id ASC").
+		Find(&products).Error
+	return products, err
+}
+
+// DuplicateGroups returns up to limit sets of non-rejected products that share
+// a match key, largest sets first.
+func (r *Repository) DuplicateGroups(limit int) ([]DuplicateGroupDTOResponse, error) {
+	var keys []struct {
+		MatchKey string
+		Count    int64
+	}
+	err := r.DB.Model(&models.CatalogProduct{}).
+		Select("match_key, COUNT(*) AS count").
+		Where("status <> ? AND match_key <> '' AND retired_at IS NULL", models.CatalogStatusRejected).
+		Group("match_key").
+		Having("COUNT(*) > 1").
+		Order("count DESC, match_key ASC").
+		Limit(limit).
+		Scan(&keys).Error
+	if err != nil || len(keys) == 0 {
+		return nil, err
+	}
+
+	matchKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		matchKeys = append(matchKeys, key.MatchKey)
+	}
+
+	var products []models.CatalogProduct
+	if err := r.DB.Where("match_key IN ? AND status <> ? AND retired_at IS NULL", matchKeys, models.CatalogStatusRejected).
+		Order("id ASC").Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string][]models.CatalogProduct, len(matchKeys))
+	for _, product := range products {
+		byKey[product.MatchKey] = append(byKey[product.MatchKey], product)
+	}
+
+	groups := make([]DuplicateGroupDTOResponse, 0, len(matchKeys))
+	for _, key := range matchKeys {
+		groups = append(groups, DuplicateGroupDTOResponse{MatchKey: key, Products: byKey[key]})
+	}
+	return groups, nil
+}
+
+// GetRedirect returns the redirect left behind when id was merged away.
+func (r *Repository) GetRedirect(id uint) (*models.CatalogRedirect, error) {
+	var redirect models.CatalogRedirect
+	if err := r.DB.Where("from_id = ?", id).First(&redirect).Error; err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// MergeCatalogProducts moves every shop listing and barcode from sourceID to
+// targetID, re-targets existing redirects, records a redirect from sourceID and removes
+// the source product, all in one transaction. A shop that already lists the
+// target has its source listing folded into that one instead. It returns how
+// many shop listings were moved or folded.
+func (r *Repository) MergeCatalogProducts(sourceID uint, targetID uint, adminID uint, at time.Time) (int64, error) {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+
+	var sources, targets []models.ShopProduct
+	if err := tx.Where("catalog_id = ?", sourceID).Find(&sources).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err := tx.Where("catalog_id = ?", targetID).Find(&targets).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	folds := pairListings(sources, targets)
+	for _, fold := range folds {
+		if err := foldListing(tx, fold.source, fold.target); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to fold shop product %d: %w", fold.source.ID, err)
+		}
+	}
+
+	moved := tx.Model(&models.ShopProduct{}).Where("catalog_id = ?", sourceID).Update("catalog_id", targetID)
+	if moved.Error != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed to repoint shop products: %w", moved.Error)
+	}
+
+	if err := tx.Model(&models.CatalogBarcode{}).Where("catalog_product_id = ?", sourceID).Update("catalog_product_id", targetID).Error; err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed to move barcodes: %w", err)
+	}
+
+	if err := tx.Model(&models.CatalogRedirect{}).Where("to_id = ?", sourceID).Update("to_id", targetID).Error; err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed to update redirects: %w", err)
+	}
+
+	redirect := models.CatalogRedirect{
+		FromID:     sourceID,
+		ToID:       targetID,
+		MergedByID: adminID,
+		MergedAt:   at,
+	}
+	if err := tx.Create(&redirect).Error; err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed to record redirect: %w", err)
+	}
+
+	if err := tx.Delete(&models.CatalogProduct{}, sourceID).Error; err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed to remove merged product: %w", err)
+	}
+
+	return moved.RowsAffected + int64(len(folds)), tx.Commit().Error
+}
+
+type listingFold struct {
+	source models.ShopProduct
+	target models.ShopProduct
+}
+
+// pairListings matches each source listing with the target listing of the
+// same shop, if the shop has one.
+func pairListings(sources []models.ShopProduct, targets []models.ShopProduct) []listingFold {
+	byShop := make(map[uint]models.ShopProduct, len(targets))
+	for _, target := range targets {
+		if _, ok := byShop[target.ShopID]; !ok {
+			byShop[target.ShopID] = target
+		}
+	}
+
+	var folds []listingFold
+	for _, source := range sources {
+		if target, ok := byShop[source.ShopID]; ok {
+			folds = append(folds, listingFold{source: source, target: target})
+		}
+	}
+	return folds
+}
+
+// foldListing merges a shop's source listing into its target listing: stock
+// is added up, cart lines and reservations move across, and the source
+// listing is deleted. Both steps are written to the inventory log.
+func foldListing(tx *gorm.DB, source models.ShopProduct, target models.ShopProduct) error {
+	if err := tx.Model(&models.ShopProduct{}).Where("id = ?", target.ID).
+		Update("stock", gorm.Expr("stock + ?", source.Stock)).Error; err != nil {
+		return err
+	}
+
+	// A user with both listings in the cart keeps one line with the sum.
+	if err := tx.Exec(`UPDATE cart_items t SET quantity = t.quantity + s.quantity
+        FROM cart_items s
+        WHERE t.shop_product_id = ? AND s.shop_product_id = ? AND s.user_id = t.user_id`,
+		target.ID, source.ID).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("shop_product_id = ? AND user_id IN (?)", source.ID,
+		tx.Model(&models.CartItem{}).Select("user_id").Where("shop_product_id = ?", target.ID)).
+		Delete(&models.CartItem{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.CartItem{}).Where("shop_product_id = ?", source.ID).
+		Update("shop_product_id", target.ID).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.StockReservation{}).Where("shop_product_id = ?", source.ID).
+		Update("shop_product_id", target.ID).Error; err != nil {
+		return err
+	}
+
+	changes := []models.InventoryChange{
+		{
+			ShopID:        target.ShopID,
+			ShopProductID: target.ID,
+			Action:        models.InventoryActionUpdated,
+			StockBefore:   target.Stock,
+			StockAfter:    target.Stock + source.Stock,
+			PriceBefore:   target.Price,
+			PriceAfter:    target.Price,
+		},
+		{
+			ShopID:        source.ShopID,
+			ShopProductID: source.ID,
+			Action:        models.InventoryActionDeleted,
+			StockBefore:   source.Stock,
+			PriceBefore:   source.Price,
+		},
+	}
+	if err := tx.Create(&changes).Error; err != nil {
+		return err
+	}
+
+	return tx.Delete(&models.ShopProduct{}, source.ID).Error
+}
+
+// Suggest searches approved products, plus the pending submissions of shopID
+// when it is not 0. When categorySlug is set only products filed under that
+// category or one of its descendants are returned.
+func (r *Repository) Suggest(keyword string, categorySlug string, limit int, shopID uint) (*[]models.CatalogProduct, error) {
 	var products []models.CatalogProduct
 
 	// Convert keyword to lowercase for case-insensitive search
 	searchPattern := "%" + strings.ToLower(keyword) + "%"
 
+	query := r.DB
+	if categorySlug != "" {
+		query = query.Where("category_id IN ("+models.CategorySubtreeSQL+")", categorySlug)
+	}
+
 	// Query with enhanced search across multiple fields
-	result := r.DB.
+	result := query.
 		Limit(limit).
-		Where("LOWER(name) LIKE ? OR LOWER(brand) LIKE ? OR LOWER(category) LIKE ? OR LOWER(desciption) LIKE ?",
-					searchPattern, searchPattern, searchPattern, searchPattern).
+		Where("(LOWER(name) LIKE ? OR LOWER(brand) LIKE ? OR LOWER(category) LIKE ? OR LOWER(description) LIKE ?)",
+			searchPattern, searchPattern, searchPattern, searchPattern).
+		Where("(status = ? OR (status = ? AND submitted_by_shop_id = ?))",
+			models.CatalogStatusApproved, models.CatalogStatusPending, shopID).
+		Where("retired_at IS NULL").
 		Order("name ASC"). // Order by name for consistent results
 		Find(&products)
 
@@ -38,3 +490,62 @@ func (r *Repository) Suggest(keyword string, limit int) (*[]models.CatalogProduc
 	}
 	return &products, nil
 }
+
+// FindNearbyAvailability lists in-stock ShopProduct rows for the catalog
+// product (or keyword match when catalogID is 0) at shops within radius metres.
+func (r *Repository) FindNearbyAvailability(catalogID uint, keyword string, lat float64, lon float64, radius float64, sortBy string, limit int) ([]NearbyProductDTOResponse, error) {
+	var products []NearbyProductDTOResponse
+
+	orderBy := "distance ASC"
+	if sortBy == "price" {
+		orderBy = "effective_price ASC, distance ASC"
+	}
+
+	args := []interface{}{lon, lat, lon, lat, radius}
+	productFilter := "cp.id = ?"
+	if catalogID > 0 {
+		args = append(args, catalogID)
+	} else {
+		searchPattern := "%" + strings.ToLower(keyword) + "%"
+		productFilter = "(LOWER(cp.name) LIKE ? OR LOWER(cp.brand) LIKE ?)"
+		args = append(args, searchPattern, searchPattern)
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+        SELECT
+            sp.id AS shop_product_id,
+            sp.catalog_id,
+            cp.name AS product_name,
+            cp.brand,
+            s.id AS shop_id,
+            s.name AS shop_name,
+            s.address,
+            s.latitude,
+            s.longitude,
+            `+models.ShopOpenNowSQL+` AS is_open,
+            sp.price,
+            sp.discount,
+            ROUND(sp.price * (1 - sp.discount / 100), 2) AS effective_price,
+            sp.stock,
+            ST_Distance(s.location, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography) AS distance
+        FROM shop_products sp
+        JOIN shops s ON s.id = sp.shop_id
+        JOIN catalog_products cp ON cp.id = sp.catalog_id
+        WHERE sp.is_available AND sp.stock > 0
+            AND s.suspended_at IS NULL
+            AND cp.status <> 'rejected'
+            AND cp.retired_at IS NULL
+            AND ST_DWithin(s.location, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography, ?)
+            AND %s
+        ORDER BY %s
+        LIMIT ?
+    `, productFilter, orderBy)
+
+	result := r.DB.Raw(query, args...).Scan(&products)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return products, nil
+}
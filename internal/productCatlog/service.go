@@ -1,6 +1,47 @@
 package productcatlog
 
-import "shop-near-u/internal/models"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProductQueryRequired      = errors.New("catalog_id or keyword is required")
+	ErrCatalogProductNotFound    = errors.New("catalog product not found")
+	ErrCatalogProductInUse       = errors.New("catalog product is still listed by shops, retire with cascade to remove the listings")
+	ErrCatalogProductRetired     = errors.New("catalog product has been retired")
+	ErrCatalogProductNotEditable = errors.New("only admins, or the submitting shop while the product is pending, may change it")
+	ErrCatalogProductNotPending  = errors.New("catalog product is not pending review")
+	ErrInvalidCatalogStatus      = errors.New("invalid status, use pending, approved or rejected")
+	ErrDuplicateCatalogProduct   = errors.New("a matching catalog product already exists")
+	ErrBarcodeTaken              = errors.New("barcode is already assigned")
+	ErrBarcodeNotFound           = errors.New("no catalog product has this barcode")
+	ErrCatalogImageNotFound      = errors.New("image not found among your uploads")
+	ErrCategoryNotFound          = errors.New("category not found")
+	ErrMergeIntoSelf             = errors.New("cannot merge a catalog product into itself")
+	ErrMergeTargetNotApproved    = errors.New("catalog products can only be merged into an approved product")
+)
+
+// Editor identifies who is changing a catalog product: an admin, or the shop
+// that submitted it.
+type Editor struct {
+	AdminID uint
+	ShopID  uint
+}
+
+func (e Editor) canEdit(product *models.CatalogProduct) bool {
+	if e.AdminID != 0 {
+		return true
+	}
+	return e.ShopID != 0 && product.Status == models.CatalogStatusPending &&
+		product.SubmittedByShopID != nil && *product.SubmittedByShopID == e.ShopID
+}
 
 type Service struct {
 	repository *Repository
@@ -10,17 +51,485 @@ func NewService(r *Repository) *Service {
 	return &Service{repository: r}
 }
 
-func (s *Service) CreateCatalogProduct(product *CreateCatalogProductDTO) error {
+// SubmitCatalogProduct stores a shop's new product as pending until an admin
+// reviews it.
+// Submissions matching a product the shop can already use are refused with
+// ErrDuplicateCatalogProduct naming the existing ID.
+func (s *Service) SubmitCatalogProduct(product *CreateCatalogProductDTO, shopID uint) (*models.CatalogProduct, error) {
 	catalogProduct := &models.CatalogProduct{
-		Name:       product.Name,
-		Brand:      product.Brand,
-		Category:   product.Category,
-		Desciption: product.Description,
-		ImageURL:   product.ImageURL,
+		Name:              product.Name,
+		Brand:             product.Brand,
+		Description:       product.Description,
+		ImageURL:          product.ImageURL,
+		Status:            models.CatalogStatusPending,
+		SubmittedByShopID: &shopID,
+	}
+	if err := s.setCategory(catalogProduct, product.CategoryID, product.Category); err != nil {
+		return nil, err
+	}
+
+	duplicates, err := s.FindDuplicateCandidates(catalogProduct.Name, catalogProduct.Brand, catalogProduct.Category, shopID)
+	if err != nil {
+		return nil, err
+	}
+	if len(duplicates) > 0 {
+		return nil, fmt.Errorf("%w: use catalog product %d", ErrDuplicateCatalogProduct, duplicates[0].ID)
+	}
+
+	barcodes := make([]models.CatalogBarcode, 0, len(product.Barcodes))
+	seen := make(map[string]bool, len(product.Barcodes))
+	for _, code := range product.Barcodes {
+		gtin, err := s.availableGTIN(code)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[gtin] {
+			seen[gtin] = true
+			barcodes = append(barcodes, models.CatalogBarcode{GTIN: gtin})
+		}
+	}
+
+	catalogProduct.Barcodes = barcodes
+	if product.ImageID != nil {
+		if err := s.attachImage(catalogProduct, *product.ImageID, shopID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repository.CreateCatalogProduct(catalogProduct); err != nil {
+		return nil, err
+	}
+	return catalogProduct, nil
+}
+
+// FindDuplicateCandidates lists the products shopID can see that normalise
+// to the same name, brand and category.
+func (s *Service) FindDuplicateCandidates(name, brand, category string, shopID uint) ([]models.CatalogProduct, error) {
+	return s.repository.FindByMatchKey(models.CatalogMatchKey(name, brand, category), shopID)
+}
+
+func (s *Service) ListDuplicateGroups(limit int) ([]DuplicateGroupDTOResponse, error) {
+	return s.repository.DuplicateGroups(limit)
+}
+
+// MergeCatalogProducts folds sourceID into targetID. Shop listings move to the
+// target, or merge into the shop's existing target listing, and sourceID
+// keeps resolving to the target through a redirect.
+func (s *Service) MergeCatalogProducts(sourceID uint, targetID uint, adminID uint) (*MergeCatalogProductDTOResponse, error) {
+	if sourceID == targetID {
+		return nil, ErrMergeIntoSelf
+	}
+	if _, err := s.GetCatalogProduct(sourceID); err != nil {
+		return nil, err
+	}
+	target, err := s.GetCatalogProduct(targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.RetiredAt != nil {
+		return nil, ErrCatalogProductRetired
+	}
+	if target.Status != models.CatalogStatusApproved {
+		return nil, ErrMergeTargetNotApproved
+	}
+
+	moved, err := s.repository.MergeCatalogProducts(sourceID, targetID, adminID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &MergeCatalogProductDTOResponse{
+		Product:       target,
+		MergedID:      sourceID,
+		ListingsMoved: moved,
+	}, nil
+}
+
+// ResolveCatalogID follows the redirect left by a merge, returning id
+// unchanged when it was never merged away.
+func (s *Service) ResolveCatalogID(id uint) (uint, error) {
+	redirect, err := s.repository.GetRedirect(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return id, nil
+		}
+		return 0, err
+	}
+	return redirect.ToID, nil
+}
+
+func (s *Service) GetSubmissions(shopID uint) ([]models.CatalogProduct, error) {
+	return s.repository.GetSubmissions(shopID)
+}
+
+func (s *Service) ListByStatus(status string, page int, limit int) ([]models.CatalogProduct, int64, error) {
+	switch status {
+	case "", models.CatalogStatusPending, models.CatalogStatusApproved, models.CatalogStatusRejected:
+	default:
+		return nil, 0, ErrInvalidCatalogStatus
+	}
+	return s.repository.ListByStatus(status, page, limit)
+}
+
+func (s *Service) ApproveCatalogProduct(id uint, reviewerID uint) (*models.CatalogProduct, error) {
+	return s.review(id, models.CatalogStatusApproved, reviewerID, "")
+}
+
+func (s *Service) RejectCatalogProduct(id uint, reviewerID uint, reason string) (*models.CatalogProduct, error) {
+	return s.review(id, models.CatalogStatusRejected, reviewerID, reason)
+}
+
+func (s *Service) review(id uint, status string, reviewerID uint, reason string) (*models.CatalogProduct, error) {
+	if _, err := s.GetCatalogProduct(id); err != nil {
+		return nil, err
+	}
+
+	reviewed, err := s.repository.Review(id, status, reviewerID, reason, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !reviewed {
+		return nil, ErrCatalogProductNotPending
+	}
+	return s.GetCatalogProduct(id)
+}
+
+func (s *Service) GetCatalogProduct(id uint) (*models.CatalogProduct, error) {
+	product, err := s.repository.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCatalogProductNotFound
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+// GetVisibleCatalogProduct returns the product behind id, following merge
+// redirects, if shopID may see it. Products hidden from the caller are
+// reported as not found.
+func (s *Service) GetVisibleCatalogProduct(id uint, shopID uint) (*models.CatalogProduct, error) {
+	resolved, err := s.ResolveCatalogID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.GetCatalogProduct(resolved)
+	if err != nil {
+		return nil, err
+	}
+	if !product.VisibleTo(shopID) {
+		return nil, ErrCatalogProductNotFound
+	}
+	return product, nil
+}
+
+// UpdateCatalogProduct applies the fields present in dto and records the edit
+// as a revision. Edits that change nothing are not recorded.
+func (s *Service) UpdateCatalogProduct(id uint, dto *UpdateCatalogProductDTO, editor Editor) (*models.CatalogProduct, error) {
+	product, err := s.GetCatalogProduct(id)
+	if err != nil {
+		return nil, err
+	}
+	if product.RetiredAt != nil {
+		return nil, ErrCatalogProductRetired
+	}
+	if !editor.canEdit(product) {
+		return nil, ErrCatalogProductNotEditable
+	}
+
+	before := *product
+	if dto.Name != nil {
+		product.Name = *dto.Name
+	}
+	if dto.Brand != nil {
+		product.Brand = *dto.Brand
+	}
+	if dto.Category != nil || dto.CategoryID != nil {
+		category := product.Category
+		if dto.Category != nil {
+			category = *dto.Category
+		}
+		if err := s.setCategory(product, dto.CategoryID, category); err != nil {
+			return nil, err
+		}
+	}
+	if dto.Description != nil {
+		product.Description = *dto.Description
+	}
+	if dto.ImageURL != nil {
+		product.ImageURL = *dto.ImageURL
+		product.ImageID = nil
+		product.Images = nil
+	}
+	if dto.ImageID != nil {
+		if err := s.attachImage(product, *dto.ImageID, editor.ShopID); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := catalogChanges(&before, product)
+	if len(changes) == 0 {
+		return product, nil
 	}
-	return s.repository.CreateCatalogProduct(catalogProduct)
+
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+	revision := &models.CatalogProductRevision{
+		CatalogProductID: product.ID,
+		Changes:          string(encoded),
+	}
+	if editor.AdminID != 0 {
+		revision.EditedByUserID = &editor.AdminID
+	} else {
+		revision.EditedByShopID = &editor.ShopID
+	}
+
+	if err := s.repository.UpdateCatalogProduct(product, revision); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// availableGTIN validates a barcode and checks that no product carries it yet.
+func (s *Service) availableGTIN(code string) (string, error) {
+	gtin, err := utils.NormalizeGTIN(code)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := s.repository.FindBarcode(gtin)
+	if err == nil {
+		return "", fmt.Errorf("%w: %s belongs to catalog product %d", ErrBarcodeTaken, gtin, existing.CatalogProductID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	return gtin, nil
+}
+
+// LookupBarcode finds the product carrying a scanned barcode, if shopID may
+// see it.
+func (s *Service) LookupBarcode(code string, shopID uint) (*models.CatalogProduct, error) {
+	gtin, err := utils.NormalizeGTIN(code)
+	if err != nil {
+		return nil, err
+	}
+
+	barcode, err := s.repository.FindBarcode(gtin)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBarcodeNotFound
+		}
+		return nil, err
+	}
+
+	product, err := s.GetCatalogProduct(barcode.CatalogProductID)
+	if err != nil {
+		return nil, err
+	}
+	if !product.VisibleTo(shopID) {
+		return nil, ErrBarcodeNotFound
+	}
+	return product, nil
+}
+
+// AddBarcode gives the product another barcode, typically for a different
+// pack size.
+func (s *Service) AddBarcode(id uint, dto *AddBarcodeDTORequest, editor Editor) (*models.CatalogBarcode, error) {
+	product, err := s.GetCatalogProduct(id)
+	if err != nil {
+		return nil, err
+	}
+	if product.RetiredAt != nil {
+		return nil, ErrCatalogProductRetired
+	}
+	if !editor.canEdit(product) {
+		return nil, ErrCatalogProductNotEditable
+	}
+
+	gtin, err := s.availableGTIN(dto.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	barcode := &models.CatalogBarcode{
+		CatalogProductID: product.ID,
+		GTIN:             gtin,
+		PackSize:         dto.PackSize,
+	}
+	if err := s.repository.AddBarcode(barcode); err != nil {
+		return nil, err
+	}
+	return barcode, nil
 }
 
-func (s *Service) SuggestCatalogProducts(keyword string, limit int) (*[]models.CatalogProduct, error) {
-	return s.repository.Suggest(keyword, limit)
+func (s *Service) RemoveBarcode(id uint, barcodeID uint, editor Editor) error {
+	product, err := s.GetCatalogProduct(id)
+	if err != nil {
+		return err
+	}
+	if !editor.canEdit(product) {
+		return ErrCatalogProductNotEditable
+	}
+
+	deleted, err := s.repository.DeleteBarcode(barcodeID, product.ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrBarcodeNotFound
+	}
+	return nil
+}
+
+// attachImage points the product at an uploaded image. Shops may only use
+// their own uploads; shopID is 0 for admins.
+func (s *Service) attachImage(product *models.CatalogProduct, imageID uint, shopID uint) error {
+	usable, err := s.repository.ImageUsableBy(imageID, shopID)
+	if err != nil {
+		return err
+	}
+	if !usable {
+		return ErrCatalogImageNotFound
+	}
+	product.ImageID = &imageID
+	product.ImageURL = models.ImageURL(imageID)
+	product.Images = models.NewImageVariantURLs(imageID)
+	return nil
+}
+
+// setCategory files the product under a category. An explicit categoryID
+// must exist and its name becomes the category text; otherwise the text is
+// kept and linked to the category with the same slug, if there is one.
+func (s *Service) setCategory(product *models.CatalogProduct, categoryID *uint, name string) error {
+	if categoryID != nil {
+		category, err := s.repository.GetCategory(*categoryID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCategoryNotFound
+		}
+		if err != nil {
+			return err
+		}
+		product.Category = category.Name
+		product.CategoryID = &category.ID
+		return nil
+	}
+
+	product.Category = name
+	category, err := s.repository.FindCategoryBySlug(models.Slugify(name))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		product.CategoryID = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	product.CategoryID = &category.ID
+	return nil
+}
+
+// catalogChanges lists the editable fields that differ between before and after.
+func catalogChanges(before, after *models.CatalogProduct) map[string]FieldChangeDTO {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"name", before.Name, after.Name},
+		{"brand", before.Brand, after.Brand},
+		{"category", before.Category, after.Category},
+		{"description", before.Description, after.Description},
+		{"image_url", before.ImageURL, after.ImageURL},
+	}
+
+	changes := make(map[string]FieldChangeDTO)
+	for _, field := range fields {
+		if field.old != field.new {
+			changes[field.name] = FieldChangeDTO{From: field.old, To: field.new}
+		}
+	}
+	return changes
+}
+
+func (s *Service) GetRevisions(id uint) ([]CatalogRevisionDTOResponse, error) {
+	revisions, err := s.repository.GetRevisions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]CatalogRevisionDTOResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		var changes map[string]FieldChangeDTO
+		if err := json.Unmarshal([]byte(revision.Changes), &changes); err != nil {
+			return nil, fmt.Errorf("failed to decode revision %d: %w", revision.ID, err)
+		}
+		response = append(response, CatalogRevisionDTOResponse{
+			ID:             revision.ID,
+			EditedByUserID: revision.EditedByUserID,
+			EditedByShopID: revision.EditedByShopID,
+			Changes:        changes,
+			CreatedAt:      revision.CreatedAt,
+		})
+	}
+	return response, nil
+}
+
+// RetireCatalogProduct withdraws a product from the catalog. While shops still
+// list it the call fails with ErrCatalogProductInUse unless cascade is set, in
+// which case those listings are removed along with their carts and holds.
+func (s *Service) RetireCatalogProduct(id uint, cascade bool, editor Editor) (*RetireCatalogProductDTOResponse, error) {
+	product, err := s.GetCatalogProduct(id)
+	if err != nil {
+		return nil, err
+	}
+	if product.RetiredAt != nil {
+		return nil, ErrCatalogProductRetired
+	}
+	if !editor.canEdit(product) {
+		return nil, ErrCatalogProductNotEditable
+	}
+
+	if !cascade {
+		count, err := s.repository.CountShopProducts(id)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, ErrCatalogProductInUse
+		}
+	}
+
+	now := time.Now()
+	removed, err := s.repository.RetireCatalogProduct(id, now)
+	if err != nil {
+		return nil, err
+	}
+	product.RetiredAt = &now
+
+	return &RetireCatalogProductDTOResponse{
+		Product:         product,
+		ListingsRemoved: removed,
+	}, nil
+}
+
+// SuggestCatalogProducts returns approved products; shopID, when not 0, also
+// sees its own pending submissions. A non-empty category narrows the results
+// to that category and everything beneath it.
+func (s *Service) SuggestCatalogProducts(keyword string, category string, limit int, shopID uint) (*[]models.CatalogProduct, error) {
+	return s.repository.Suggest(keyword, models.Slugify(category), limit, shopID)
+}
+
+func (s *Service) FindNearbyAvailability(catalogID uint, keyword string, lat float64, lon float64, radius float64, sortBy string, limit int) ([]NearbyProductDTOResponse, error) {
+	if catalogID == 0 && keyword == "" {
+		return nil, ErrProductQueryRequired
+	}
+	if catalogID > 0 {
+		resolved, err := s.ResolveCatalogID(catalogID)
+		if err != nil {
+			return nil, err
+		}
+		catalogID = resolved
+	}
+	return s.repository.FindNearbyAvailability(catalogID, keyword, lat, lon, radius, sortBy, limit)
 }
@@ -0,0 +1,51 @@
+package productcatlog
+
+import (
+	"shop-near-u/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogChanges(t *testing.T) {
+	before := &models.CatalogProduct{Name: "Amul Butter", Brand: "Amul", Category: "Dairy", Description: "500g"}
+	after := *before
+	assert.Empty(t, catalogChanges(before, &after))
+
+	after.Name = "Amul Butter 500g"
+	after.Description = ""
+	changes := catalogChanges(before, &after)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, FieldChangeDTO{From: "Amul Butter", To: "Amul Butter 500g"}, changes["name"])
+	assert.Equal(t, FieldChangeDTO{From: "500g", To: ""}, changes["description"])
+}
+
+func TestEditorCanEdit(t *testing.T) {
+	submitter := uint(4)
+	pending := &models.CatalogProduct{Status: models.CatalogStatusPending, SubmittedByShopID: &submitter}
+	approved := &models.CatalogProduct{Status: models.CatalogStatusApproved, SubmittedByShopID: &submitter}
+
+	assert.True(t, Editor{AdminID: 1}.canEdit(approved))
+	assert.True(t, Editor{ShopID: 4}.canEdit(pending))
+	assert.False(t, Editor{ShopID: 5}.canEdit(pending))
+	assert.False(t, Editor{ShopID: 4}.canEdit(approved))
+	assert.False(t, Editor{}.canEdit(pending))
+}
+
+func TestPairListings(t *testing.T) {
+	sources := []models.ShopProduct{
+		{ID: 10, ShopID: 1, CatalogID: 7, Stock: 3},
+		{ID: 11, ShopID: 2, CatalogID: 7, Stock: 5},
+	}
+	targets := []models.ShopProduct{
+		{ID: 20, ShopID: 1, CatalogID: 9, Stock: 4},
+		{ID: 21, ShopID: 3, CatalogID: 9, Stock: 1},
+	}
+
+	folds := pairListings(sources, targets)
+	assert.Len(t, folds, 1)
+	assert.Equal(t, uint(10), folds[0].source.ID)
+	assert.Equal(t, uint(20), folds[0].target.ID)
+
+	assert.Empty(t, pairListings(sources, nil))
+}
@@ -0,0 +1,7 @@
+package reservation
+
+type CreateReservationDTORequest struct {
+	ShopProductID   uint `json:"shop_product_id" binding:"required"`
+	Quantity        int  `json:"quantity" binding:"required,gt=0"`
+	DurationMinutes int  `json:"duration_minutes" binding:"omitempty,gte=1,lte=1440"`
+}
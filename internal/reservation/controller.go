@@ -0,0 +1,179 @@
+package reservation
+
+import (
+	"errors"
+	"net/http"
+	"shop-near-u/internal/middlewares"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Controller struct {
+	service *Service
+}
+
+func NewController(s *Service) *Controller {
+	return &Controller{service: s}
+}
+
+func (ctrl *Controller) Reserve(c *gin.Context) {
+	var dto CreateReservationDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	reservation, err := ctrl.service.Reserve(&dto, u.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponseSimple(c, http.StatusNotFound, "product not found")
+		case errors.Is(err, ErrInsufficientStock):
+			utils.ErrorResponseSimple(c, http.StatusConflict, err.Error())
+		case errors.Is(err, ErrProductUnavailable):
+			utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		default:
+			utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Stock reserved successfully", reservation)
+}
+
+func (ctrl *Controller) GetMyReservations(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	reservations, err := ctrl.service.GetUserReservations(u.ID)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reservations retrieved successfully", reservations)
+}
+
+func (ctrl *Controller) Cancel(c *gin.Context) {
+	reservationID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid reservation ID")
+		return
+	}
+
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	if err := ctrl.service.Cancel(reservationID, u.ID); err != nil {
+		reservationErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reservation cancelled successfully", nil)
+}
+
+func (ctrl *Controller) GetShopReservations(c *gin.Context) {
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, "failed to parse shop data")
+		return
+	}
+
+	reservations, err := ctrl.service.GetActiveShopReservations(shop.ID)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Active reservations retrieved successfully", reservations)
+}
+
+func (ctrl *Controller) Complete(c *gin.Context) {
+	reservationID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid reservation ID")
+		return
+	}
+
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, "failed to parse shop data")
+		return
+	}
+
+	order, err := ctrl.service.Convert(reservationID, shop.ID)
+	if err != nil {
+		reservationErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reservation completed successfully", order)
+}
+
+func reservationErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrReservationNotFound):
+		utils.ErrorResponseSimple(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrReservationInactive):
+		utils.ErrorResponseSimple(c, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
+	repo := NewRepository(db)
+	svc := NewService(repo)
+	ctrl := NewController(svc)
+
+	reservations := r.Group("/reservations")
+	reservations.Use(middlewares.RequireUserAuth(db))
+	{
+		reservations.POST("", ctrl.Reserve)
+		reservations.GET("", ctrl.GetMyReservations)
+		reservations.DELETE("/:id", ctrl.Cancel)
+	}
+
+	shopReservations := r.Group("/shop/reservations")
+	shopReservations.Use(middlewares.RequireShopOwnerAuth(db))
+	{
+		shopReservations.GET("", ctrl.GetShopReservations)
+		shopReservations.POST("/:id/complete", ctrl.Complete)
+	}
+}
@@ -0,0 +1,270 @@
+package reservation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"shop-near-u/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientStock   = errors.New("insufficient stock")
+	ErrProductUnavailable  = errors.New("product is not available")
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationInactive = errors.New("reservation is no longer active")
+)
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{DB: db}
+}
+
+// Create takes the reserved quantity out of stock and stores the hold in a
+// single transaction.
+func (r *Repository) Create(reservation *models.StockReservation) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	var product models.ShopProduct
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, reservation.ShopProductID).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("product with ID %d not found: %w", reservation.ShopProductID, err)
+	}
+
+	if !product.IsAvailable {
+		tx.Rollback()
+		return ErrProductUnavailable
+	}
+	if product.Stock < reservation.Quantity {
+		tx.Rollback()
+		return fmt.Errorf("%w: %d left", ErrInsufficientStock, product.Stock)
+	}
+
+	if err := tx.Model(&models.ShopProduct{}).Where("id = ?", product.ID).
+		Update("stock", gorm.Expr("stock - ?", reservation.Quantity)).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	reservation.ShopID = product.ShopID
+	reservation.Status = models.ReservationStatusActive
+	if err := tx.Create(reservation).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (r *Repository) GetByUserID(userID uint) ([]models.StockReservation, error) {
+	var reservations []models.StockReservation
+	result := r.DB.Preload("ShopProduct.CatalogProduct").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&reservations)
+	return reservations, result.Error
+}
+
+// GetActiveByShopID lists holds that are active and not yet past their expiry,
+// even if the expiry sweep has not caught up with them.
+func (r *Repository) GetActiveByShopID(shopID uint) ([]models.StockReservation, error) {
+	var reservations []models.StockReservation
+	result := r.DB.Preload("ShopProduct.CatalogProduct").
+		Where("shop_id = ? AND status = ? AND expires_at > ?", shopID, models.ReservationStatusActive, time.Now()).
+		Order("expires_at ASC").
+		Find(&reservations)
+	return reservations, result.Error
+}
+
+// Cancel releases a customer's active hold back into stock.
+func (r *Repository) Cancel(reservationID uint, userID uint) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	reservation, err := lockActive(tx, reservationID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if reservation.UserID != userID {
+		tx.Rollback()
+		return ErrReservationNotFound
+	}
+
+	if err := release(tx, []models.StockReservation{*reservation}, models.ReservationStatusCancelled); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Convert turns a shop's active hold into a completed order. Stock was already
+// taken when the hold was created, so it is not touched again.
+func (r *Repository) Convert(reservationID uint, shopID uint) (*models.Order, error) {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	reservation, err := lockActive(tx, reservationID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if reservation.ShopID != shopID {
+		tx.Rollback()
+		return nil, ErrReservationNotFound
+	}
+
+	var product models.ShopProduct
+	if err := tx.Preload("CatalogProduct").First(&product, reservation.ShopProductID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	now := time.Now()
+	subtotal := roundPrice(product.Price * float64(reservation.Quantity))
+	total := roundPrice(product.EffectivePrice() * float64(reservation.Quantity))
+	order := &models.Order{
+		UserID:         reservation.UserID,
+		ShopID:         reservation.ShopID,
+		Status:         models.OrderStatusCompleted,
+		FulfilmentType: models.FulfilmentPickup,
+		Subtotal:       subtotal,
+		Total:          total,
+		Discount:       roundPrice(subtotal - total),
+		CompletedAt:    &now,
+		Items: []models.OrderItem{{
+			ShopProductID: product.ID,
+			CatalogID:     product.CatalogID,
+			Name:          product.CatalogProduct.Name,
+			UnitPrice:     product.Price,
+			Discount:      product.Discount,
+			Quantity:      reservation.Quantity,
+			LineTotal:     total,
+		}},
+	}
+	if err := tx.Create(order).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Model(&models.StockReservation{}).Where("id = ?", reservation.ID).Updates(map[string]interface{}{
+		"status":       models.ReservationStatusConverted,
+		"order_id":     order.ID,
+		"converted_at": now,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// ExpireDue releases up to limit holds whose window has passed. Rows are
+// claimed with FOR UPDATE SKIP LOCKED, so several replicas can sweep at the
+// same time without releasing the same hold twice.
+func (r *Repository) ExpireDue(limit int) (int, error) {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+
+	var due []models.StockReservation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND expires_at <= ?", models.ReservationStatusActive, time.Now()).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&due).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if len(due) == 0 {
+		tx.Rollback()
+		return 0, nil
+	}
+
+	if err := release(tx, due, models.ReservationStatusExpired); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, err
+	}
+	return len(due), nil
+}
+
+// lockActive locks the reservation row and checks it can still be acted on.
+func lockActive(tx *gorm.DB, reservationID uint) (*models.StockReservation, error) {
+	var reservation models.StockReservation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reservation, reservationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReservationNotFound
+		}
+		return nil, err
+	}
+
+	if reservation.Status != models.ReservationStatusActive || !reservation.ExpiresAt.After(time.Now()) {
+		return nil, ErrReservationInactive
+	}
+	return &reservation, nil
+}
+
+// release returns the held quantities to stock and marks the holds with status.
+func release(tx *gorm.DB, reservations []models.StockReservation, status string) error {
+	ids := make([]uint, 0, len(reservations))
+	for _, reservation := range reservations {
+		if err := tx.Model(&models.ShopProduct{}).Where("id = ?", reservation.ShopProductID).
+			Update("stock", gorm.Expr("stock + ?", reservation.Quantity)).Error; err != nil {
+			return err
+		}
+		ids = append(ids, reservation.ID)
+	}
+
+	return tx.Model(&models.StockReservation{}).Where("id IN ?", ids).Update("status", status).Error
+}
+
+func roundPrice(value float64) float64 {
+	return math.Round(value*100) / 100
+}
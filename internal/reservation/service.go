@@ -0,0 +1,101 @@
+package reservation
+
+import (
+	"context"
+	"log"
+	"os"
+	"shop-near-u/internal/models"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultWindowMinutes = 30
+	expiryBatchSize      = 100
+)
+
+type Service struct {
+	repository *Repository
+	window     time.Duration
+}
+
+// NewService reads the default hold window from RESERVATION_WINDOW_MINUTES,
+// falling back to 30 minutes.
+func NewService(r *Repository) *Service {
+	minutes, err := strconv.Atoi(os.Getenv("RESERVATION_WINDOW_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = defaultWindowMinutes
+	}
+	return &Service{repository: r, window: time.Duration(minutes) * time.Minute}
+}
+
+func (s *Service) Reserve(dto *CreateReservationDTORequest, userID uint) (*models.StockReservation, error) {
+	window := s.window
+	if dto.DurationMinutes > 0 {
+		window = time.Duration(dto.DurationMinutes) * time.Minute
+	}
+
+	reservation := &models.StockReservation{
+		ShopProductID: dto.ShopProductID,
+		UserID:        userID,
+		Quantity:      dto.Quantity,
+		ExpiresAt:     time.Now().Add(window),
+	}
+
+	if err := s.repository.Create(reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (s *Service) GetUserReservations(userID uint) ([]models.StockReservation, error) {
+	return s.repository.GetByUserID(userID)
+}
+
+func (s *Service) GetActiveShopReservations(shopID uint) ([]models.StockReservation, error) {
+	return s.repository.GetActiveByShopID(shopID)
+}
+
+func (s *Service) Cancel(reservationID uint, userID uint) error {
+	return s.repository.Cancel(reservationID, userID)
+}
+
+func (s *Service) Convert(reservationID uint, shopID uint) (*models.Order, error) {
+	return s.repository.Convert(reservationID, shopID)
+}
+
+// ExpireDue releases every hold whose window has passed, batch by batch.
+func (s *Service) ExpireDue() (int, error) {
+	total := 0
+	for {
+		released, err := s.repository.ExpireDue(expiryBatchSize)
+		total += released
+		if err != nil || released < expiryBatchSize {
+			return total, err
+		}
+	}
+}
+
+// RunExpiryWorker sweeps expired holds every interval until ctx is done. Each
+// replica can run its own worker; the repository makes concurrent sweeps safe.
+func (s *Service) RunExpiryWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		released, err := s.ExpireDue()
+		if err != nil {
+			log.Printf("reservation expiry failed: %v", err)
+			continue
+		}
+		if released > 0 {
+			log.Printf("released %d expired reservations", released)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package reservation
+
+import (
+	"fmt"
+	"os"
+	"shop-near-u/internal/database"
+	"shop-near-u/internal/models"
+	"testing"
+	"time"
+
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/restayway/gogis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// openTestDB returns the migrated database configured in the environment and
+// skips the test when none is configured.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	if os.Getenv("DB_HOST") == "" {
+		t.Skip("DB_HOST not set")
+	}
+	return database.New().GetDB()
+}
+
+type fixture struct {
+	db      *gorm.DB
+	service *Service
+	shop    models.Shop
+	user    models.User
+	listing models.ShopProduct
+}
+
+func newFixture(t *testing.T, stock int) *fixture {
+	t.Helper()
+	db := openTestDB(t)
+	suffix := time.Now().UnixNano()
+
+	f := &fixture{db: db, service: NewService(NewRepository(db))}
+
+	f.user = models.User{Name: "reserver", Email: fmt.Sprintf("reserver-%d@example.com", suffix), Password: "x"}
+	require.NoError(t, db.Create(&f.user).Error)
+
+	f.shop = models.Shop{
+		Name:      "reservation shop",
+		OwnerName: "owner",
+		Email:     fmt.Sprintf("reservation-shop-%d@example.com", suffix),
+		Mobile:    "1234567890",
+		Type:      "grocery",
+		Password:  "x",
+		Address:   "1 test street",
+		Latitude:  13.07,
+		Longitude: 80.23,
+		Location:  gogis.Point{Lng: 80.23, Lat: 13.07},
+	}
+	require.NoError(t, db.Create(&f.shop).Error)
+
+	catalog := models.CatalogProduct{Name: fmt.Sprintf("Reserved Product %d", suffix), Category: "Test", Status: models.CatalogStatusApproved}
+	require.NoError(t, db.Create(&catalog).Error)
+
+	f.listing = models.ShopProduct{ShopID: f.shop.ID, CatalogID: catalog.ID, Price: 50, Discount: 10, Stock: stock, IsAvailable: true}
+	require.NoError(t, db.Create(&f.listing).Error)
+
+	t.Cleanup(func() {
+		db.Where("order_id IN (?)", db.Model(&models.Order{}).Select("id").Where("shop_id = ?", f.shop.ID)).Delete(&models.OrderItem{})
+		db.Where("shop_id = ?", f.shop.ID).Delete(&models.StockReservation{})
+		db.Where("shop_id = ?", f.shop.ID).Delete(&models.Order{})
+		db.Delete(&models.ShopProduct{}, f.listing.ID)
+		db.Delete(&models.CatalogProduct{}, catalog.ID)
+		db.Delete(&models.Shop{}, f.shop.ID)
+		db.Delete(&models.User{}, f.user.ID)
+	})
+	return f
+}
+
+func (f *fixture) stock(t *testing.T) int {
+	t.Helper()
+	var listing models.ShopProduct
+	require.NoError(t, f.db.First(&listing, f.listing.ID).Error)
+	return listing.Stock
+}
+
+func (f *fixture) status(t *testing.T, id uint) string {
+	t.Helper()
+	var reservation models.StockReservation
+	require.NoError(t, f.db.First(&reservation, id).Error)
+	return reservation.Status
+}
+
+func TestReserveTakesStockAndCancelReturnsIt(t *testing.T) {
+	f := newFixture(t, 10)
+
+	reservation, err := f.service.Reserve(&CreateReservationDTORequest{ShopProductID: f.listing.ID, Quantity: 3}, f.user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 7, f.stock(t))
+
+	_, err = f.service.Reserve(&CreateReservationDTORequest{ShopProductID: f.listing.ID, Quantity: 8}, f.user.ID)
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+
+	require.NoError(t, f.service.Cancel(reservation.ID, f.user.ID))
+	assert.Equal(t, 10, f.stock(t))
+	assert.Equal(t, models.ReservationStatusCancelled, f.status(t, reservation.ID))
+
+	assert.ErrorIs(t, f.service.Cancel(reservation.ID, f.user.ID), ErrReservationInactive)
+}
+
+func TestExpireDueReturnsStock(t *testing.T) {
+	f := newFixture(t, 10)
+
+	reservation, err := f.service.Reserve(&CreateReservationDTORequest{ShopProductID: f.listing.ID, Quantity: 4}, f.user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 6, f.stock(t))
+
+	require.NoError(t, f.db.Model(&models.StockReservation{}).Where("id = ?", reservation.ID).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+	released, err := f.service.ExpireDue()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, released, 1)
+	assert.Equal(t, 10, f.stock(t))
+	assert.Equal(t, models.ReservationStatusExpired, f.status(t, reservation.ID))
+
+	_, err = f.service.Convert(reservation.ID, f.shop.ID)
+	assert.ErrorIs(t, err, ErrReservationInactive)
+}
+
+func TestConvertCreatesCompletedOrder(t *testing.T) {
+	f := newFixture(t, 10)
+
+	reservation, err := f.service.Reserve(&CreateReservationDTORequest{ShopProductID: f.listing.ID, Quantity: 2}, f.user.ID)
+	require.NoError(t, err)
+
+	_, err = f.service.Convert(reservation.ID, f.shop.ID+1)
+	assert.ErrorIs(t, err, ErrReservationNotFound)
+
+	order, err := f.service.Convert(reservation.ID, f.shop.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusCompleted, order.Status)
+	assert.Equal(t, models.FulfilmentPickup, order.FulfilmentType)
+	assert.Equal(t, 100.0, order.Subtotal)
+	assert.Equal(t, 90.0, order.Total)
+	require.Len(t, order.Items, 1)
+	assert.Equal(t, 2, order.Items[0].Quantity)
+
+	// Stock was taken when the hold was made and stays taken.
+	assert.Equal(t, 8, f.stock(t))
+	assert.Equal(t, models.ReservationStatusConverted, f.status(t, reservation.ID))
+
+	_, err = f.service.Convert(reservation.ID, f.shop.ID)
+	assert.ErrorIs(t, err, ErrReservationInactive)
+}
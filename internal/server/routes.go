@@ -1,9 +1,21 @@
 package server
 
 import (
+	"fmt"
+	"log"
 	"net/http"
+	"shop-near-u/internal/admin"
+	"shop-near-u/internal/cart"
+	"shop-near-u/internal/category"
+	"shop-near-u/internal/geocoding"
+	"shop-near-u/internal/media"
+	"shop-near-u/internal/merchant"
+	"shop-near-u/internal/order"
 	productcatlog "shop-near-u/internal/productCatlog"
+	"shop-near-u/internal/reservation"
 	"shop-near-u/internal/shop"
+	"shop-near-u/internal/staff"
+	"shop-near-u/internal/storage"
 	"shop-near-u/internal/user"
 	"shop-near-u/internal/utils"
 
@@ -11,7 +23,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func (s *Server) RegisterRoutes() http.Handler {
+func (s *Server) RegisterRoutes() (http.Handler, error) {
 	r := gin.Default()
 
 	r.Use(cors.New(cors.Config{
@@ -25,11 +37,33 @@ func (s *Server) RegisterRoutes() http.Handler {
 
 	r.GET("/health", s.healthHandler)
 
-	user.RegisterRoutes(r, s.db.GetDB())
-	shop.RegisterRoutes(r, s.db.GetDB())
+	geocoder, err := geocoding.New()
+	if err != nil {
+		return nil, fmt.Errorf("geocoder: %w", err)
+	}
+	store, err := storage.New()
+	if err != nil {
+		return nil, fmt.Errorf("storage: %w", err)
+	}
+
+	if err := admin.Bootstrap(s.db.GetDB()); err != nil {
+		log.Printf("admin bootstrap failed: %v", err)
+	}
+
+	user.RegisterRoutes(r, s.db.GetDB(), geocoder)
+	shop.RegisterRoutes(r, s.db.GetDB(), geocoder)
+	merchant.RegisterRoutes(r, s.db.GetDB(), geocoder)
+	staff.RegisterRoutes(r, s.db.GetDB())
 	productcatlog.RegisterRoutes(r, s.db.GetDB())
+	category.RegisterRoutes(r, s.db.GetDB())
+	order.RegisterRoutes(r, s.db.GetDB())
+	cart.RegisterRoutes(r, s.db.GetDB())
+	reservation.RegisterRoutes(r, s.db.GetDB())
+	geocoding.RegisterRoutes(r, geocoder)
+	media.RegisterRoutes(r, s.db.GetDB(), store)
+	admin.RegisterRoutes(r, s.db.GetDB())
 
-	return r
+	return r, nil
 }
 
 func (s *Server) HelloWorldHandler(c *gin.Context) {
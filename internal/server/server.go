@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,6 +11,7 @@ import (
 	_ "github.com/joho/godotenv/autoload"
 
 	"shop-near-u/internal/database"
+	"shop-near-u/internal/reservation"
 )
 
 type Server struct {
@@ -18,7 +20,7 @@ type Server struct {
 	db database.Service
 }
 
-func NewServer() *http.Server {
+func NewServer() (*http.Server, error) {
 	port, _ := strconv.Atoi(os.Getenv("PORT"))
 	NewServer := &Server{
 		port: port,
@@ -26,14 +28,25 @@ func NewServer() *http.Server {
 		db: database.New(),
 	}
 
+	handler, err := NewServer.RegisterRoutes()
+	if err != nil {
+		return nil, err
+	}
+
 	// Declare Server config
 	server := &http.Server{
 		Addr:         fmt.Sprintf("0.0.0.0:%d", NewServer.port),
-		Handler:      NewServer.RegisterRoutes(),
+		Handler:      handler,
 		IdleTimeout:  time.Minute,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 30 * time.Second,
 	}
 
-	return server
+	return server, nil
+}
+
+// RunWorkers runs the background jobs until ctx is cancelled.
+func RunWorkers(ctx context.Context) {
+	db := database.New().GetDB()
+	reservation.NewService(reservation.NewRepository(db)).RunExpiryWorker(ctx, time.Minute)
 }
@@ -1,5 +1,12 @@
 package shop
 
+import (
+	"shop-near-u/internal/models"
+	"time"
+
+	"github.com/restayway/gogis"
+)
+
 type ShopRegisterDTORequest struct {
 	Name      string `json:"name" binding:"required"`
 	OwnerName string `json:"owner_name" binding:"required"`
@@ -8,9 +15,14 @@ type ShopRegisterDTORequest struct {
 	Mobile    string `json:"mobile" binding:"required"`
 	Password  string `json:"password" binding:"required"`
 
-	Address   string  `json:"address" binding:"required"`
-	Latitude  float64 `json:"latitude" binding:"required"`
-	Longitude float64 `json:"longitude" binding:"required"`
+	// Latitude and Longitude must be sent together or both omitted, in which
+	// case they are geocoded from Address.
+	Address   string   `json:"address" binding:"required"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,gte=-180,lte=180"`
+
+	SupportsDelivery bool    `json:"supports_delivery"`
+	DeliveryRadius   float64 `json:"delivery_radius" binding:"gte=0"`
 }
 
 type ShopRegisterDTOResponse struct {
@@ -26,6 +38,32 @@ type ShopRegisterDTOResponse struct {
 	SubscriberCount uint    `json:"subscriber_count"`
 	IsOpen          bool    `json:"is_open"`
 	Token           string  `json:"token"`
+
+	SupportsDelivery bool    `json:"supports_delivery"`
+	DeliveryRadius   float64 `json:"delivery_radius"`
+
+	Gallery []ShopImageDTOResponse `json:"gallery,omitempty"`
+}
+
+// ShopImageDTOResponse is one gallery picture with its variant URLs.
+type ShopImageDTOResponse struct {
+	ID      uint                     `json:"id"`
+	Caption string                   `json:"caption"`
+	Images  *models.ImageVariantURLs `json:"images"`
+}
+
+// UpdateShopProfileDTORequest changes only the fields that are present.
+// Latitude and Longitude must be sent together; a new Address without them
+// is geocoded.
+type UpdateShopProfileDTORequest struct {
+	Name      *string `json:"name" binding:"omitempty,min=1,max=100"`
+	OwnerName *string `json:"owner_name" binding:"omitempty,min=1,max=100"`
+	Type      *string `json:"type" binding:"omitempty,min=1,max=50"`
+	Mobile    *string `json:"mobile" binding:"omitempty,min=1,max=15"`
+
+	Address   *string  `json:"address" binding:"omitempty,min=1,max=255"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,gte=-180,lte=180"`
 }
 
 type ShopLoginDTORequest struct {
@@ -33,6 +71,11 @@ type ShopLoginDTORequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type ChangePasswordDTORequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
 type ShopLoginDTOResponse struct {
 	Token string `json:"token"`
 }
@@ -44,6 +87,36 @@ type NearByShopsDTORespone struct {
 	Latitude  float64 `json:"latitude" binding:"required"`
 	Longitude float64 `json:"longitude" binding:"required"`
 	Distance  float64 `json:"distance" binding:"required"`
+	IsOpen    bool    `json:"is_open"`
+
+	Type             string `json:"type"`
+	SupportsDelivery bool   `json:"supports_delivery"`
+	SubscriberCount  uint   `json:"subscriber_count"`
+
+	Location gogis.Point `json:"-"`
+}
+
+const (
+	SortByDistance   = "distance"
+	SortByPopularity = "popularity"
+	SortByName       = "name"
+)
+
+// NearbyShopsFilter narrows the nearby-shops query. Zero values leave the
+// corresponding filter off.
+type NearbyShopsFilter struct {
+	Latitude  float64
+	Longitude float64
+	Radius    float64
+	Limit     int
+
+	Type             string
+	OpenNow          bool
+	SupportsDelivery bool
+	MinSubscribers   uint
+	CatalogID        uint
+	Category         string
+	SortBy           string
 }
 
 type SubscribeShopDTOResponse struct {
@@ -61,6 +134,10 @@ type GetShopDetailsDTOResponse struct {
 	Name            string `json:"name"`
 	SubscriberCount uint   `json:"subscriber_count"`
 	IsSubscribed    bool   `json:"is_subscribed"`
+	IsOpen          bool   `json:"is_open"`
+
+	ClosedPermanently bool                   `json:"closed_permanently"`
+	Gallery           []ShopImageDTOResponse `json:"gallery"`
 }
 
 type SubscribedShopDTOResponse struct {
@@ -73,4 +150,86 @@ type SubscribedShopDTOResponse struct {
 	Longitude       float64 `json:"longitude"`
 	SubscriberCount uint    `json:"subscriber_count"`
 	IsOpen          bool    `json:"is_open"`
+
+	RelocatedAt       *time.Time `json:"relocated_at,omitempty"`
+	ClosedPermanently bool       `json:"closed_permanently"`
+}
+
+type DeliveryFeeTierDTO struct {
+	MaxDistance float64 `json:"max_distance" binding:"required,gt=0"`
+	Fee         float64 `json:"fee" binding:"gte=0"`
+}
+
+type UpdateDeliverySettingsDTORequest struct {
+	SupportsDelivery  bool                 `json:"supports_delivery"`
+	DeliveryRadius    float64              `json:"delivery_radius" binding:"gte=0"`
+	FreeDeliveryAbove float64              `json:"free_delivery_above" binding:"gte=0"`
+	FeeTiers          []DeliveryFeeTierDTO `json:"fee_tiers" binding:"dive"`
+}
+
+type DeliverySettingsDTOResponse struct {
+	SupportsDelivery  bool                 `json:"supports_delivery"`
+	DeliveryRadius    float64              `json:"delivery_radius"`
+	FreeDeliveryAbove float64              `json:"free_delivery_above"`
+	FeeTiers          []DeliveryFeeTierDTO `json:"fee_tiers"`
+}
+
+type OpeningHoursDTO struct {
+	Weekday  int    `json:"weekday" binding:"gte=0,lte=6"`
+	OpensAt  string `json:"opens_at" binding:"required"`
+	ClosesAt string `json:"closes_at" binding:"required"`
+}
+
+type UpdateOpeningHoursDTORequest struct {
+	TimeZone string            `json:"time_zone" binding:"required"`
+	Hours    []OpeningHoursDTO `json:"hours" binding:"dive"`
+}
+
+type HoursExceptionDTORequest struct {
+	Date     string `json:"date" binding:"required"`
+	Closed   bool   `json:"closed"`
+	OpensAt  string `json:"opens_at"`
+	ClosesAt string `json:"closes_at"`
+	Note     string `json:"note" binding:"max=255"`
+}
+
+type OpeningHoursDTOResponse struct {
+	TimeZone            string                      `json:"time_zone"`
+	IsOpen              bool                        `json:"is_open"`
+	StatusOverrideUntil *time.Time                  `json:"status_override_until,omitempty"`
+	Hours               []models.ShopOpeningHours   `json:"hours"`
+	Exceptions          []models.ShopHoursException `json:"exceptions"`
+}
+
+// BoundingBox is a map viewport in WGS84 degrees.
+type BoundingBox struct {
+	MinLatitude  float64
+	MinLongitude float64
+	MaxLatitude  float64
+	MaxLongitude float64
+}
+
+type MapShopDTOResponse struct {
+	ID        uint    `json:"id"`
+	Name      string  `json:"name"`
+	Address   string  `json:"address"`
+	Type      string  `json:"type"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	IsOpen    bool    `json:"is_open"`
+
+	Location gogis.Point `json:"-"`
+}
+
+type MapClusterDTOResponse struct {
+	Count     int     `json:"count"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type MapViewportDTOResponse struct {
+	Zoom      int                     `json:"zoom"`
+	Clustered bool                    `json:"clustered"`
+	Shops     []MapShopDTOResponse    `json:"shops,omitempty"`
+	Clusters  []MapClusterDTOResponse `json:"clusters,omitempty"`
 }
@@ -3,10 +3,13 @@ package shop
 import (
 	"errors"
 	"net/http"
+	"shop-near-u/internal/geocoding"
 	"shop-near-u/internal/middlewares"
 	"shop-near-u/internal/models"
 	"shop-near-u/internal/product"
 	"shop-near-u/internal/utils"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -28,12 +31,17 @@ func (ctrl *Controller) RegisterShop(c *gin.Context) {
 		return
 	}
 
-	shop, err := ctrl.shopService.RegisterShop(&dto)
+	shop, err := ctrl.shopService.RegisterShop(c.Request.Context(), &dto)
 	if err != nil {
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
 			utils.ErrorResponseSimple(c, 409, "shop already exists")
 			return
 		}
+		if errors.Is(err, ErrDeliveryRadiusRequired) || errors.Is(err, ErrAddressNotGeocoded) ||
+			errors.Is(err, ErrIncompleteCoordinates) {
+			utils.ErrorResponseSimple(c, 400, err.Error())
+			return
+		}
 		utils.ErrorResponseSimple(c, 500, err.Error())
 		return
 	}
@@ -63,6 +71,9 @@ func (ctrl *Controller) RegisterShop(c *gin.Context) {
 		Latitude:  shop.Latitude,
 		Longitude: shop.Longitude,
 		Token:     token,
+
+		SupportsDelivery: shop.SupportsDelivery,
+		DeliveryRadius:   shop.DeliveryRadius,
 	})
 }
 
@@ -75,6 +86,10 @@ func (ctrl *Controller) Login(c *gin.Context) {
 
 	shop, err := ctrl.shopService.AuthenticateShop(&dto)
 	if err != nil {
+		if errors.Is(err, ErrShopClosed) || errors.Is(err, ErrShopSuspended) {
+			utils.ErrorResponseSimple(c, 403, err.Error())
+			return
+		}
 		utils.ErrorResponseSimple(c, 500, err.Error())
 		return
 	}
@@ -104,10 +119,70 @@ func (ctrl *Controller) Login(c *gin.Context) {
 		Longitude: shop.Longitude,
 		Token:     token,
 		IsOpen:    shop.IsOpen,
+
+		SupportsDelivery: shop.SupportsDelivery,
+		DeliveryRadius:   shop.DeliveryRadius,
 	})
 
 }
 
+func (ctrl *Controller) Logout(c *gin.Context) {
+	utils.SetCookie("", -1, c)
+
+	utils.SuccessResponse(c, http.StatusOK, "Successfully logged out", nil)
+}
+
+func (ctrl *Controller) ChangePassword(c *gin.Context) {
+	var dto ChangePasswordDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, 400, err.Error())
+		return
+	}
+
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, 401, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, 500, "failed to parse shop data")
+		return
+	}
+
+	if err := ctrl.shopService.ChangePassword(shop.ID, dto.OldPassword, dto.NewPassword); err != nil {
+		utils.ErrorResponseSimple(c, 400, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password changed successfully", nil)
+}
+
+// DeleteAccount permanently closes the shop and logs the owner out.
+func (ctrl *Controller) DeleteAccount(c *gin.Context) {
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, 401, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, 500, "failed to parse shop data")
+		return
+	}
+
+	if err := ctrl.shopService.CloseShop(shop.ID); err != nil {
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	utils.SetCookie("", -1, c)
+
+	utils.SuccessResponse(c, http.StatusOK, "Shop closed permanently", nil)
+}
+
 func (ctrl *Controller) GetShopProfile(c *gin.Context) {
 	shopInterface, exists := c.Get("shop")
 	if !exists {
@@ -121,7 +196,86 @@ func (ctrl *Controller) GetShopProfile(c *gin.Context) {
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Shop profile retrieved successfully", ShopRegisterDTOResponse{
+	isOpen, err := ctrl.shopService.IsShopOpen(shop.ID)
+	if err != nil {
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+	shop.IsOpen = isOpen
+
+	response := toShopProfileResponse(shop)
+	if response.Gallery, err = ctrl.shopService.GetGallery(shop.ID); err != nil {
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shop profile retrieved successfully", response)
+}
+
+func (ctrl *Controller) UpdateShopProfile(c *gin.Context) {
+	var dto UpdateShopProfileDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, 400, err.Error())
+		return
+	}
+
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, 401, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, 500, "failed to parse shop data")
+		return
+	}
+
+	updated, err := ctrl.shopService.UpdateProfile(c.Request.Context(), &shop, &dto)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrIncompleteCoordinates):
+			utils.ErrorResponseSimple(c, 400, err.Error())
+		case errors.Is(err, ErrAddressNotGeocoded):
+			utils.ErrorResponseSimple(c, 422, err.Error())
+		default:
+			utils.ErrorResponseSimple(c, 500, err.Error())
+		}
+		return
+	}
+
+	isOpen, err := ctrl.shopService.IsShopOpen(updated.ID)
+	if err != nil {
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+	updated.IsOpen = isOpen
+
+	utils.SuccessResponse(c, http.StatusOK, "Shop profile updated successfully", toShopProfileResponse(*updated))
+}
+
+func (ctrl *Controller) GetAddressHistory(c *gin.Context) {
+	shopID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, 400, "invalid shop ID")
+		return
+	}
+
+	history, err := ctrl.shopService.GetAddressHistory(shopID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponseSimple(c, 404, "shop not found")
+			return
+		}
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Address history retrieved successfully", history)
+}
+
+func toShopProfileResponse(shop models.Shop) ShopRegisterDTOResponse {
+	return ShopRegisterDTOResponse{
 		ID:              shop.ID,
 		Name:            shop.Name,
 		OwnerName:       shop.OwnerName,
@@ -133,7 +287,10 @@ func (ctrl *Controller) GetShopProfile(c *gin.Context) {
 		Longitude:       shop.Longitude,
 		SubscriberCount: shop.SubscriberCount,
 		IsOpen:          shop.IsOpen,
-	})
+
+		SupportsDelivery: shop.SupportsDelivery,
+		DeliveryRadius:   shop.DeliveryRadius,
+	}
 }
 
 func (ctrl *Controller) AddProduct(c *gin.Context) {
@@ -155,8 +312,20 @@ func (ctrl *Controller) AddProduct(c *gin.Context) {
 		return
 	}
 
-	err := ctrl.productService.AddProduct(&dto, shop.ID)
+	err := ctrl.productService.AddProduct(&dto, shop.ID, staffIDFromContext(c))
 	if err != nil {
+		if errors.Is(err, product.ErrCatalogProductUnavailable) {
+			utils.ErrorResponseSimple(c, 403, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrInvalidBarcode) || errors.Is(err, product.ErrBarcodeMismatch) {
+			utils.ErrorResponseSimple(c, 400, err.Error())
+			return
+		}
+		if errors.Is(err, product.ErrBarcodeNotFound) {
+			utils.ErrorResponseSimple(c, 404, err.Error())
+			return
+		}
 		utils.ErrorResponseSimple(c, 500, err.Error())
 		return
 	}
@@ -220,8 +389,24 @@ func (ctrl *Controller) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	err := ctrl.productService.UpdateProduct(&dto)
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, 401, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, 500, "failed to parse shop data")
+		return
+	}
+
+	err := ctrl.productService.UpdateProduct(&dto, shop.ID, staffIDFromContext(c))
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponseSimple(c, 404, "product not found")
+			return
+		}
 		utils.ErrorResponseSimple(c, 500, err.Error())
 		return
 	}
@@ -242,7 +427,19 @@ func (ctrl *Controller) DeleteProduct(c *gin.Context) {
 		return
 	}
 
-	err = ctrl.productService.DeleteProduct(productID)
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, 401, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, 500, "failed to parse shop data")
+		return
+	}
+
+	err = ctrl.productService.DeleteProduct(productID, shop.ID, staffIDFromContext(c))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			utils.ErrorResponseSimple(c, 404, "product not found")
@@ -255,6 +452,49 @@ func (ctrl *Controller) DeleteProduct(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Product deleted successfully", nil)
 }
 
+func (ctrl *Controller) GetInventoryChanges(c *gin.Context) {
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, 401, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, 500, "failed to parse shop data")
+		return
+	}
+
+	limit, err := utils.ParseIntParam(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, 400, "invalid limit")
+		return
+	}
+
+	changes, err := ctrl.productService.GetInventoryChanges(shop.ID, limit)
+	if err != nil {
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Inventory changes retrieved successfully", changes)
+}
+
+// staffIDFromContext returns the staff member behind the request, or nil when
+// the shop owner is acting.
+func staffIDFromContext(c *gin.Context) *uint {
+	staffInterface, exists := c.Get("staff")
+	if !exists {
+		return nil
+	}
+
+	staff, ok := staffInterface.(models.ShopStaff)
+	if !ok {
+		return nil
+	}
+	return &staff.ID
+}
+
 func (ctrl *Controller) IsShopOpen(c *gin.Context) {
 	shopIDParam := c.Param("id")
 	shopId, err := utils.ParseUintParam(shopIDParam)
@@ -264,13 +504,17 @@ func (ctrl *Controller) IsShopOpen(c *gin.Context) {
 		return
 	}
 
-	shop, err := ctrl.shopService.GetShopByID(shopId)
+	isOpen, err := ctrl.shopService.IsShopOpen(shopId)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponseSimple(c, 404, "shop not found")
+			return
+		}
 		utils.ErrorResponseSimple(c, 500, err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Shop status retrieved successfully", shop.IsOpen)
+	utils.SuccessResponse(c, http.StatusOK, "Shop status retrieved successfully", isOpen)
 }
 
 func (ctrl *Controller) UpdateShopStatus(c *gin.Context) {
@@ -287,7 +531,7 @@ func (ctrl *Controller) UpdateShopStatus(c *gin.Context) {
 	}
 
 	status := c.Query("status")
-	// status can be "open" or "closed"
+	// status can be "open", "closed" or "auto" to follow the opening hours again
 	if status == "" {
 		utils.ErrorResponseSimple(c, 400, "status is required")
 		return
@@ -299,12 +543,30 @@ func (ctrl *Controller) UpdateShopStatus(c *gin.Context) {
 		isOpen = true
 	case "closed":
 		isOpen = false
+	case "auto":
+		if err := ctrl.shopService.ClearStatusOverride(shop.ID); err != nil {
+			utils.ErrorResponseSimple(c, 500, err.Error())
+			return
+		}
+		utils.SuccessResponse(c, http.StatusOK, "Shop status updated successfully", nil)
+		return
 	default:
 		utils.ErrorResponseSimple(c, 400, "invalid status")
 		return
 	}
 
-	err := ctrl.shopService.UpdateShopStatus(shop.ID, isOpen)
+	// the override lasts until the given RFC 3339 time, or the end of the local day
+	var until *time.Time
+	if untilParam := c.Query("until"); untilParam != "" {
+		parsed, err := time.Parse(time.RFC3339, untilParam)
+		if err != nil || !parsed.After(time.Now()) {
+			utils.ErrorResponseSimple(c, 400, "invalid until, expected a future RFC 3339 time")
+			return
+		}
+		until = &parsed
+	}
+
+	err := ctrl.shopService.UpdateShopStatus(shop.ID, isOpen, until)
 	if err != nil {
 		utils.ErrorResponseSimple(c, 500, err.Error())
 		return
@@ -319,10 +581,27 @@ func (ctrl *Controller) NearByShop(c *gin.Context) {
 	radStr := c.DefaultQuery("radius", "5000")
 	limit := c.DefaultQuery("limit", "10")
 
-	lat, err := utils.ParseFloatParam(latStr)
-	if err != nil {
-		utils.ErrorResponseSimple(c, 400, "invalid latitude")
-		return
+	var lat, lon float64
+	var err error
+	if latStr == "" && lonStr == "" {
+		// Fall back to the signed-in user's default saved address.
+		lat, lon, err = defaultUserLocation(c)
+		if err != nil {
+			utils.ErrorResponseSimple(c, 400, err.Error())
+			return
+		}
+	} else {
+		lat, err = utils.ParseFloatParam(latStr)
+		if err != nil {
+			utils.ErrorResponseSimple(c, 400, "invalid latitude")
+			return
+		}
+
+		lon, err = utils.ParseFloatParam(lonStr)
+		if err != nil {
+			utils.ErrorResponseSimple(c, 400, "invalid longitude")
+			return
+		}
 	}
 
 	lim, err := utils.ParseIntParam(limit)
@@ -331,27 +610,120 @@ func (ctrl *Controller) NearByShop(c *gin.Context) {
 		return
 	}
 
-	lon, err := utils.ParseFloatParam(lonStr)
+	radius, err := utils.ParseFloatParam(radStr)
 	if err != nil {
-		utils.ErrorResponseSimple(c, 400, "invalid longitude")
+		utils.ErrorResponseSimple(c, 400, "invalid radius")
 		return
 	}
 
-	radius, err := utils.ParseFloatParam(radStr)
-	if err != nil {
-		utils.ErrorResponseSimple(c, 400, "invalid radius")
+	filter := NearbyShopsFilter{
+		Latitude:  lat,
+		Longitude: lon,
+		Radius:    radius,
+		Limit:     lim,
+		Type:      c.Query("type"),
+		Category:  c.Query("category"),
+		SortBy:    c.DefaultQuery("sort", SortByDistance),
+	}
+
+	switch filter.SortBy {
+	case SortByDistance, SortByPopularity, SortByName:
+	default:
+		utils.ErrorResponseSimple(c, 400, "invalid sort, use distance, popularity or name")
 		return
 	}
 
-	shops, err := ctrl.shopService.GetNearbyShops(lat, lon, radius, lim)
+	if openNow := c.Query("open_now"); openNow != "" {
+		filter.OpenNow, err = strconv.ParseBool(openNow)
+		if err != nil {
+			utils.ErrorResponseSimple(c, 400, "invalid open_now")
+			return
+		}
+	}
+
+	if supportsDelivery := c.Query("supports_delivery"); supportsDelivery != "" {
+		filter.SupportsDelivery, err = strconv.ParseBool(supportsDelivery)
+		if err != nil {
+			utils.ErrorResponseSimple(c, 400, "invalid supports_delivery")
+			return
+		}
+	}
+
+	if minSubscribers := c.Query("min_subscribers"); minSubscribers != "" {
+		filter.MinSubscribers, err = utils.ParseUintParam(minSubscribers)
+		if err != nil {
+			utils.ErrorResponseSimple(c, 400, "invalid min_subscribers")
+			return
+		}
+	}
+
+	if catalogID := c.Query("catalog_id"); catalogID != "" {
+		filter.CatalogID, err = utils.ParseUintParam(catalogID)
+		if err != nil {
+			utils.ErrorResponseSimple(c, 400, "invalid catalog_id")
+			return
+		}
+	}
+
+	shops, err := ctrl.shopService.GetNearbyShops(filter)
 	if err != nil {
 		utils.ErrorResponseSimple(c, 500, err.Error())
 		return
 	}
 
+	if utils.WantsGeoJSON(c) {
+		utils.SuccessResponse(c, http.StatusOK, "Nearby shops retrieved successfully", nearbyShopsToGeoJSON(shops))
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Nearby shops retrieved successfully", shops)
 }
 
+func (ctrl *Controller) GetShopsInViewport(c *gin.Context) {
+	var box BoundingBox
+	var err error
+
+	params := []struct {
+		name  string
+		value *float64
+	}{
+		{"min_lat", &box.MinLatitude},
+		{"min_lon", &box.MinLongitude},
+		{"max_lat", &box.MaxLatitude},
+		{"max_lon", &box.MaxLongitude},
+	}
+	for _, param := range params {
+		*param.value, err = utils.ParseFloatParam(c.Query(param.name))
+		if err != nil {
+			utils.ErrorResponseSimple(c, 400, "invalid "+param.name)
+			return
+		}
+	}
+
+	zoom, err := utils.ParseIntParam(c.Query("zoom"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, 400, "invalid zoom")
+		return
+	}
+
+	viewport, err := ctrl.shopService.GetShopsInViewport(box, zoom)
+	if err != nil {
+		if errors.Is(err, ErrInvalidBoundingBox) {
+			utils.ErrorResponseSimple(c, 400, err.Error())
+			return
+		}
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	if utils.WantsGeoJSON(c) {
+		utils.SuccessResponse(c, http.StatusOK, "Map shops retrieved successfully", viewportToGeoJSON(viewport))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Map shops retrieved successfully", viewport)
+}
+
 func (ctrl *Controller) GetShopProducts(c *gin.Context) {
 	shopIDParam := c.Param("id")
 	if shopIDParam == "" {
@@ -390,9 +762,61 @@ func (ctrl *Controller) GetShopProducts(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Shop products retrieved successfully", products)
 }
 
-func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
+func (ctrl *Controller) GetDeliverySettings(c *gin.Context) {
+	shopID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, 400, "invalid shop ID")
+		return
+	}
+
+	settings, err := ctrl.shopService.GetDeliverySettings(shopID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponseSimple(c, 404, "shop not found")
+			return
+		}
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Delivery settings retrieved successfully", settings)
+}
+
+func (ctrl *Controller) UpdateDeliverySettings(c *gin.Context) {
+	var dto UpdateDeliverySettingsDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, 400, err.Error())
+		return
+	}
+
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, 401, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, 500, "failed to parse shop data")
+		return
+	}
+
+	settings, err := ctrl.shopService.UpdateDeliverySettings(shop.ID, &dto)
+	if err != nil {
+		if errors.Is(err, ErrDeliveryRadiusRequired) {
+			utils.ErrorResponseSimple(c, 400, err.Error())
+			return
+		}
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Delivery settings updated successfully", settings)
+}
+
+func RegisterRoutes(r *gin.Engine, db *gorm.DB, geocoder geocoding.Geocoder) {
 	repo := NewRepository(db)
-	shopService := NewService(repo)
+	shopService := NewService(repo, geocoder)
 	productService := product.NewService(product.NewRepository(db))
 	ctrl := NewController(shopService, productService)
 
@@ -401,12 +825,28 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
 		shops.POST("/register", ctrl.RegisterShop)
 		shops.POST("/login", ctrl.Login)
 		shops.GET("/profile", middlewares.RequireShopOwnerAuth(db), ctrl.GetShopProfile)
-		shops.GET("", ctrl.NearByShop)
+		shops.PUT("/profile", middlewares.RequireShopOwnerAuth(db), ctrl.UpdateShopProfile)
+		shops.POST("/logout", middlewares.RequireShopOwnerAuth(db), ctrl.Logout)
+		shops.POST("/change-password", middlewares.RequireShopOwnerAuth(db), ctrl.ChangePassword)
+		shops.DELETE("/delete-account", middlewares.RequireShopOwnerAuth(db), ctrl.DeleteAccount)
+		shops.GET("", middlewares.OptionalUserAuth(db), ctrl.NearByShop)
+		shops.GET("/map", ctrl.GetShopsInViewport)
+		shops.GET("/serving", ctrl.GetShopsServingPoint)
 		shops.GET("/is_open/:id", ctrl.IsShopOpen)
-		shops.PUT("/status", middlewares.RequireShopOwnerAuth(db), ctrl.UpdateShopStatus)
+		shops.PUT("/status", middlewares.RequireShopPermission(db, models.PermissionToggleStatus), ctrl.UpdateShopStatus)
+		shops.PUT("/delivery", middlewares.RequireShopOwnerAuth(db), ctrl.UpdateDeliverySettings)
+		shops.PUT("/hours", middlewares.RequireShopOwnerAuth(db), ctrl.UpdateOpeningHours)
+		shops.POST("/hours/exceptions", middlewares.RequireShopOwnerAuth(db), ctrl.AddHoursException)
+		shops.DELETE("/hours/exceptions/:id", middlewares.RequireShopOwnerAuth(db), ctrl.DeleteHoursException)
+		shops.PUT("/service-area", middlewares.RequireShopOwnerAuth(db), ctrl.SetServiceArea)
+		shops.DELETE("/service-area", middlewares.RequireShopOwnerAuth(db), ctrl.ClearServiceArea)
 
 		shops.GET("/:id", middlewares.RequireUserAuth(db), ctrl.GetShopDetails)
 		shops.GET("/:id/products", ctrl.GetShopProducts)
+		shops.GET("/:id/delivery", ctrl.GetDeliverySettings)
+		shops.GET("/:id/hours", ctrl.GetOpeningHours)
+		shops.GET("/:id/service-area", ctrl.GetServiceArea)
+		shops.GET("/:id/address-history", ctrl.GetAddressHistory)
 		shops.POST("/:id/subscribe", middlewares.RequireUserAuth(db), ctrl.SubscribeShop)
 		shops.POST("/:id/unsubscribe", middlewares.RequireUserAuth(db), ctrl.UnsubscribeShop)
 	}
@@ -415,7 +855,7 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
 	r.GET("/user/subscriptions", middlewares.RequireUserAuth(db), ctrl.GetUserSubscriptions)
 
 	products := r.Group("/shop/products")
-	products.Use(middlewares.RequireShopOwnerAuth(db))
+	products.Use(middlewares.RequireShopPermission(db, models.PermissionManageProducts))
 	{
 		products.POST("", ctrl.AddProduct)
 		products.GET("", ctrl.GetAllProducts)
@@ -423,4 +863,21 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
 		products.PUT("", ctrl.UpdateProduct)
 		products.DELETE("/:id", ctrl.DeleteProduct)
 	}
+
+	r.GET("/shop/inventory-changes", middlewares.RequireShopOwnerAuth(db), ctrl.GetInventoryChanges)
+}
+
+// defaultUserLocation returns the coordinates of the authenticated user's
+// default address.
+func defaultUserLocation(c *gin.Context) (float64, float64, error) {
+	user, exists := c.Get("user")
+	if !exists {
+		return 0, 0, ErrLocationRequired
+	}
+
+	u, ok := user.(models.User)
+	if !ok || (u.Latitude == 0 && u.Longitude == 0) {
+		return 0, 0, ErrLocationRequired
+	}
+	return u.Latitude, u.Longitude, nil
 }
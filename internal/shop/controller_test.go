@@ -12,6 +12,7 @@ import (
 	"shop-near-u/internal/utils"
 	"strings"
 	"testing"
+	"time"
 
 	_ "github.com/joho/godotenv/autoload"
 	"github.com/stretchr/testify/assert"
@@ -30,9 +31,20 @@ type Response struct {
 	Data    Data   `json:"data,omitempty"`
 }
 
+func newRouter(t *testing.T) http.Handler {
+	t.Helper()
+	srv, err := server.NewServer()
+	require.NoError(t, err)
+	return srv.Handler
+}
+
+func coord(v float64) *float64 {
+	return &v
+}
+
 func registerUser(t *testing.T) Response {
 	t.Helper()
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	w := httptest.NewRecorder()
 
@@ -42,8 +54,8 @@ func registerUser(t *testing.T) Response {
 		Email:     "test@gmail.com",
 		Mobile:    "1234567890",
 		Address:   "no 123. test street, test state",
-		Latitude:  13.07439,
-		Longitude: 80.237617,
+		Latitude:  coord(13.07439),
+		Longitude: coord(80.237617),
 		Password:  "test@123",
 		Type:      "Test-type",
 	}
@@ -66,11 +78,11 @@ func registerProduct(t *testing.T, db *gorm.DB) uint {
 	t.Helper()
 
 	catalog := models.CatalogProduct{
-		Name:       "Test Product",
-		Category:   "Test Category",
-		Desciption: "Test Description",
-		Brand:      "Test Brand",
-		ImageURL:   "http://example.com/image.jpg",
+		Name:        "Test Product",
+		Category:    "Test Category",
+		Description: "Test Description",
+		Brand:       "Test Brand",
+		ImageURL:    "http://example.com/image.jpg",
 	}
 
 	err := db.Create(&catalog).Error
@@ -82,6 +94,9 @@ func clearTestDB(t *testing.T, db *gorm.DB) {
 	t.Helper()
 
 	// delete dependent records first to avoid FK constraint errors
+	if err := db.Exec("DELETE FROM stock_reservations").Error; err != nil {
+		t.Fatalf("failed to clear stock_reservations table: %v", err)
+	}
 	if err := db.Exec("DELETE FROM shop_products").Error; err != nil {
 		t.Fatalf("failed to clear shop_products table: %v", err)
 	}
@@ -99,7 +114,7 @@ func clearTestDB(t *testing.T, db *gorm.DB) {
 
 func TestRegisterShop(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	w := httptest.NewRecorder()
 
@@ -109,8 +124,8 @@ func TestRegisterShop(t *testing.T) {
 		Email:     "test@gmail.com",
 		Mobile:    "1234567890",
 		Address:   "no 123. test street, test state",
-		Latitude:  13.07439,
-		Longitude: 80.237617,
+		Latitude:  coord(13.07439),
+		Longitude: coord(80.237617),
 		Password:  "test@123",
 		Type:      "Test-type",
 	}
@@ -129,7 +144,7 @@ func TestRegisterShop(t *testing.T) {
 
 func TestRegisterShopToken(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	w := httptest.NewRecorder()
 
@@ -139,8 +154,8 @@ func TestRegisterShopToken(t *testing.T) {
 		Email:     "test1@gmail.com",
 		Mobile:    "1234567890",
 		Address:   "no 123. test street, test state",
-		Latitude:  12.06439,
-		Longitude: 82.237617,
+		Latitude:  coord(12.06439),
+		Longitude: coord(82.237617),
 		Password:  "test@123",
 		Type:      "Test-type",
 	}
@@ -165,7 +180,7 @@ func TestRegisterShopToken(t *testing.T) {
 
 func TestLoginShop(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	w := httptest.NewRecorder()
 
@@ -175,8 +190,8 @@ func TestLoginShop(t *testing.T) {
 		Email:     "test@gmail.com",
 		Mobile:    "1234567890",
 		Address:   "no 123. test street, test state",
-		Latitude:  13.07439,
-		Longitude: 80.237617,
+		Latitude:  coord(13.07439),
+		Longitude: coord(80.237617),
 		Password:  "test@123",
 		Type:      "Test-type",
 	}
@@ -213,7 +228,7 @@ func TestLoginShop(t *testing.T) {
 
 func TestGetShopProfile(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 	data := registerUser(t)
 
 	w := httptest.NewRecorder()
@@ -238,7 +253,7 @@ func TestGetShopProfile(t *testing.T) {
 }
 
 func TestGetShopProfile_Unauthorized(t *testing.T) {
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	w := httptest.NewRecorder()
 
@@ -250,7 +265,7 @@ func TestGetShopProfile_Unauthorized(t *testing.T) {
 
 func TestAddProduct(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 	data := registerUser(t)
 
 	w := httptest.NewRecorder()
@@ -287,7 +302,7 @@ func TestAddProduct(t *testing.T) {
 
 func TestRegisterShop_MissingRequiredFields(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	testCases := []struct {
 		name     string
@@ -352,7 +367,7 @@ func TestRegisterShop_MissingRequiredFields(t *testing.T) {
 
 func TestRegisterShop_InvalidEmail(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	w := httptest.NewRecorder()
 
@@ -362,8 +377,8 @@ func TestRegisterShop_InvalidEmail(t *testing.T) {
 		Email:     "invalid-email",
 		Mobile:    "1234567890",
 		Address:   "no 123. test street",
-		Latitude:  13.07439,
-		Longitude: 80.237617,
+		Latitude:  coord(13.07439),
+		Longitude: coord(80.237617),
 		Password:  "test@123",
 		Type:      "Test-type",
 	}
@@ -376,7 +391,7 @@ func TestRegisterShop_InvalidEmail(t *testing.T) {
 
 func TestRegisterShop_DuplicateEmail(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	// Register first shop
 	registerUser(t)
@@ -389,8 +404,8 @@ func TestRegisterShop_DuplicateEmail(t *testing.T) {
 		Email:     "test@gmail.com", // Same email
 		Mobile:    "9876543210",
 		Address:   "no 456. test street",
-		Latitude:  13.08439,
-		Longitude: 80.247617,
+		Latitude:  coord(13.08439),
+		Longitude: coord(80.247617),
 		Password:  "test@123",
 		Type:      "Test-type",
 	}
@@ -404,7 +419,7 @@ func TestRegisterShop_DuplicateEmail(t *testing.T) {
 
 func TestRegisterShop_InvalidJSON(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("POST", "/shop/register", strings.NewReader("invalid json"))
@@ -415,7 +430,7 @@ func TestRegisterShop_InvalidJSON(t *testing.T) {
 
 func TestLoginShop_InvalidCredentials(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	// Register a shop first
 	registerUser(t)
@@ -455,7 +470,7 @@ func TestLoginShop_InvalidCredentials(t *testing.T) {
 }
 
 func TestLoginShop_MissingFields(t *testing.T) {
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	testCases := []struct {
 		name string
@@ -492,7 +507,7 @@ func TestLoginShop_MissingFields(t *testing.T) {
 }
 
 func TestLoginShop_InvalidEmail(t *testing.T) {
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	w := httptest.NewRecorder()
 	dto := shop.ShopLoginDTORequest{
@@ -507,7 +522,7 @@ func TestLoginShop_InvalidEmail(t *testing.T) {
 }
 
 func TestGetShopProfile_InvalidToken(t *testing.T) {
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/shop/profile", nil)
@@ -519,7 +534,7 @@ func TestGetShopProfile_InvalidToken(t *testing.T) {
 
 func TestGetShopProfile_ExpiredToken(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	// Create an expired token
 	expiredToken := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJleHAiOjE2MDk0NTkyMDAsInVzZXJfaWQiOjEsInJvbGUiOiJzaG9wX293bmVyIn0.invalid"
@@ -534,7 +549,7 @@ func TestGetShopProfile_ExpiredToken(t *testing.T) {
 
 func TestAddProduct_Unauthorized(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	w := httptest.NewRecorder()
 	dto := product.AddProductDTORequest{
@@ -553,7 +568,7 @@ func TestAddProduct_Unauthorized(t *testing.T) {
 
 func TestAddProduct_InvalidCatalogID(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 	data := registerUser(t)
 
 	w := httptest.NewRecorder()
@@ -574,7 +589,7 @@ func TestAddProduct_InvalidCatalogID(t *testing.T) {
 
 func TestAddProduct_InvalidData(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 	data := registerUser(t)
 
 	testCases := []struct {
@@ -630,7 +645,7 @@ func TestAddProduct_InvalidData(t *testing.T) {
 
 func TestMultipleShopsRegistration(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	shops := []shop.ShopRegisterDTORequest{
 		{
@@ -639,8 +654,8 @@ func TestMultipleShopsRegistration(t *testing.T) {
 			Email:     "shop1@gmail.com",
 			Mobile:    "1111111111",
 			Address:   "Address 1",
-			Latitude:  13.01,
-			Longitude: 80.21,
+			Latitude:  coord(13.01),
+			Longitude: coord(80.21),
 			Password:  "password1",
 			Type:      "Type1",
 		},
@@ -650,8 +665,8 @@ func TestMultipleShopsRegistration(t *testing.T) {
 			Email:     "shop2@gmail.com",
 			Mobile:    "2222222222",
 			Address:   "Address 2",
-			Latitude:  13.02,
-			Longitude: 80.22,
+			Latitude:  coord(13.02),
+			Longitude: coord(80.22),
 			Password:  "password2",
 			Type:      "Type2",
 		},
@@ -661,8 +676,8 @@ func TestMultipleShopsRegistration(t *testing.T) {
 			Email:     "shop3@gmail.com",
 			Mobile:    "3333333333",
 			Address:   "Address 3",
-			Latitude:  13.03,
-			Longitude: 80.23,
+			Latitude:  coord(13.03),
+			Longitude: coord(80.23),
 			Password:  "password3",
 			Type:      "Type3",
 		},
@@ -686,7 +701,7 @@ func TestMultipleShopsRegistration(t *testing.T) {
 
 func TestRegisterAndLoginCycle(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	// Register
 	registerDTO := shop.ShopRegisterDTORequest{
@@ -695,8 +710,8 @@ func TestRegisterAndLoginCycle(t *testing.T) {
 		Email:     "cycle@gmail.com",
 		Mobile:    "9999999999",
 		Address:   "Cycle Address",
-		Latitude:  13.05,
-		Longitude: 80.25,
+		Latitude:  coord(13.05),
+		Longitude: coord(80.25),
 		Password:  "cycle@123",
 		Type:      "Cycle-Type",
 	}
@@ -747,7 +762,7 @@ func TestRegisterAndLoginCycle(t *testing.T) {
 
 func TestEdgeCaseCoordinates(t *testing.T) {
 	defer clearTestDB(t, database.New().GetDB())
-	router := server.NewServer().Handler
+	router := newRouter(t)
 
 	testCases := []struct {
 		name      string
@@ -777,6 +792,13 @@ func TestEdgeCaseCoordinates(t *testing.T) {
 			longitude: 0.1,
 			wantCode:  http.StatusCreated,
 		},
+		{
+			name:      "Equator And Prime Meridian",
+			email:     "edge7@gmail.com",
+			latitude:  0,
+			longitude: 0,
+			wantCode:  http.StatusCreated,
+		},
 		{
 			name:      "High Latitude North",
 			email:     "edge4@gmail.com",
@@ -809,8 +831,8 @@ func TestEdgeCaseCoordinates(t *testing.T) {
 				Email:     tc.email,
 				Mobile:    "8888888888",
 				Address:   "Edge Address",
-				Latitude:  tc.latitude,
-				Longitude: tc.longitude,
+				Latitude:  coord(tc.latitude),
+				Longitude: coord(tc.longitude),
 				Password:  "edge@123",
 				Type:      "Edge-Type",
 			}
@@ -822,3 +844,47 @@ func TestEdgeCaseCoordinates(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteAccount_WithReservations(t *testing.T) {
+	db := database.New().GetDB()
+	defer clearTestDB(t, db)
+	router := newRouter(t)
+	data := registerUser(t)
+
+	user := models.User{Name: "reserver", Email: "reserver@gmail.com", Password: "x"}
+	require.NoError(t, db.Create(&user).Error)
+	defer db.Delete(&user)
+
+	listing := models.ShopProduct{
+		ShopID:      data.Data.ID,
+		CatalogID:   registerProduct(t, db),
+		Price:       100,
+		Stock:       10,
+		IsAvailable: true,
+	}
+	require.NoError(t, db.Create(&listing).Error)
+
+	for _, status := range []string{models.ReservationStatusActive, models.ReservationStatusExpired} {
+		require.NoError(t, db.Create(&models.StockReservation{
+			ShopProductID: listing.ID,
+			ShopID:        data.Data.ID,
+			UserID:        user.ID,
+			Quantity:      1,
+			Status:        status,
+			ExpiresAt:     time.Now().Add(time.Hour),
+		}).Error)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/shops/delete-account", nil)
+	req.AddCookie(&http.Cookie{Name: "Authorization", Value: data.Data.Token})
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var listings, reservations int64
+	db.Model(&models.ShopProduct{}).Where("shop_id = ?", data.Data.ID).Count(&listings)
+	db.Model(&models.StockReservation{}).Where("shop_id = ?", data.Data.ID).Count(&reservations)
+	assert.Zero(t, listings)
+	assert.Zero(t, reservations)
+}
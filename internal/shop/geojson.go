@@ -0,0 +1,42 @@
+package shop
+
+import (
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/utils"
+
+	"github.com/restayway/gogis"
+)
+
+func nearbyShopsToGeoJSON(shops []NearByShopsDTORespone) utils.GeoJSONFeatureCollection {
+	features := make([]utils.GeoJSONFeature, 0, len(shops))
+	for _, shop := range shops {
+		features = append(features, utils.NewPointFeature(shop.ID, shop.Location, utils.ToProperties(shop)))
+	}
+	return utils.NewFeatureCollection(features)
+}
+
+// viewportToGeoJSON emits shops as-is and clusters as points carrying their
+// count, flagged with a "cluster" property so clients can style them apart.
+func viewportToGeoJSON(viewport *MapViewportDTOResponse) utils.GeoJSONFeatureCollection {
+	features := make([]utils.GeoJSONFeature, 0, len(viewport.Shops)+len(viewport.Clusters))
+	for _, shop := range viewport.Shops {
+		properties := utils.ToProperties(shop)
+		properties["cluster"] = false
+		features = append(features, utils.NewPointFeature(shop.ID, shop.Location, properties))
+	}
+	for _, cluster := range viewport.Clusters {
+		centroid := gogis.Point{Lng: cluster.Longitude, Lat: cluster.Latitude}
+		properties := utils.ToProperties(cluster)
+		properties["cluster"] = true
+		features = append(features, utils.NewPointFeature(nil, centroid, properties))
+	}
+	return utils.NewFeatureCollection(features)
+}
+
+func subscribedShopsToGeoJSON(shops []models.Shop) utils.GeoJSONFeatureCollection {
+	features := make([]utils.GeoJSONFeature, 0, len(shops))
+	for _, shop := range shops {
+		features = append(features, utils.NewPointFeature(shop.ID, shop.Location, utils.ToProperties(toSubscribedShopResponse(shop))))
+	}
+	return utils.NewFeatureCollection(features)
+}
@@ -0,0 +1,131 @@
+package shop
+
+import (
+	"errors"
+	"net/http"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func (ctrl *Controller) GetOpeningHours(c *gin.Context) {
+	shopID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, 400, "invalid shop ID")
+		return
+	}
+
+	hours, err := ctrl.shopService.GetOpeningHours(shopID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponseSimple(c, 404, "shop not found")
+			return
+		}
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Opening hours retrieved successfully", hours)
+}
+
+func (ctrl *Controller) UpdateOpeningHours(c *gin.Context) {
+	var dto UpdateOpeningHoursDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, 400, err.Error())
+		return
+	}
+
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, 401, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, 500, "failed to parse shop data")
+		return
+	}
+
+	if err := ctrl.shopService.UpdateOpeningHours(shop.ID, &dto); err != nil {
+		if errors.Is(err, ErrInvalidTimeZone) || errors.Is(err, ErrInvalidOpeningHours) {
+			utils.ErrorResponseSimple(c, 400, err.Error())
+			return
+		}
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	hours, err := ctrl.shopService.GetOpeningHours(shop.ID)
+	if err != nil {
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Opening hours updated successfully", hours)
+}
+
+func (ctrl *Controller) AddHoursException(c *gin.Context) {
+	var dto HoursExceptionDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, 400, err.Error())
+		return
+	}
+
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, 401, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, 500, "failed to parse shop data")
+		return
+	}
+
+	exception, err := ctrl.shopService.AddHoursException(shop.ID, &dto)
+	if err != nil {
+		if errors.Is(err, ErrInvalidDate) || errors.Is(err, ErrInvalidOpeningHours) {
+			utils.ErrorResponseSimple(c, 400, err.Error())
+			return
+		}
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Opening hours exception added successfully", exception)
+}
+
+func (ctrl *Controller) DeleteHoursException(c *gin.Context) {
+	exceptionID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, 400, "invalid exception ID")
+		return
+	}
+
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, 401, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, 500, "failed to parse shop data")
+		return
+	}
+
+	if err := ctrl.shopService.DeleteHoursException(exceptionID, shop.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponseSimple(c, 404, "exception not found")
+			return
+		}
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Opening hours exception deleted successfully", nil)
+}
@@ -0,0 +1,43 @@
+package shop
+
+import (
+	"shop-near-u/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInterval(t *testing.T) {
+	opens, closes, err := parseInterval("9:00", "24:00")
+	assert.NoError(t, err)
+	assert.Equal(t, "09:00", opens)
+	assert.Equal(t, "24:00", closes)
+
+	_, _, err = parseInterval("18:00", "09:00")
+	assert.ErrorIs(t, err, ErrInvalidOpeningHours)
+
+	_, _, err = parseInterval("9am", "17:00")
+	assert.ErrorIs(t, err, ErrInvalidOpeningHours)
+}
+
+func TestCheckOverlaps(t *testing.T) {
+	hours := []models.ShopOpeningHours{
+		{Weekday: 1, OpensAt: "14:00", ClosesAt: "20:00"},
+		{Weekday: 1, OpensAt: "09:00", ClosesAt: "13:00"},
+		{Weekday: 2, OpensAt: "09:00", ClosesAt: "20:00"},
+	}
+	assert.NoError(t, checkOverlaps(hours))
+
+	hours = append(hours, models.ShopOpeningHours{Weekday: 1, OpensAt: "12:30", ClosesAt: "14:00"})
+	assert.ErrorIs(t, checkOverlaps(hours), ErrInvalidOpeningHours)
+}
+
+func TestNextLocalMidnight(t *testing.T) {
+	now := time.Date(2026, 3, 10, 20, 0, 0, 0, time.UTC)
+
+	midnight := nextLocalMidnight("Asia/Kolkata", now)
+
+	assert.Equal(t, time.Date(2026, 3, 11, 18, 30, 0, 0, time.UTC), midnight.UTC())
+	assert.Equal(t, "2026-03-11", localDate("Asia/Kolkata", now))
+}
@@ -0,0 +1,19 @@
+package shop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterGridSize(t *testing.T) {
+	assert.Equal(t, 90.0, clusterGridSize(0))
+	assert.Equal(t, 45.0, clusterGridSize(1))
+	assert.InDelta(t, 0.02197, clusterGridSize(12), 0.00001)
+}
+
+func TestValidateBoundingBox(t *testing.T) {
+	assert.NoError(t, validateBoundingBox(BoundingBox{MinLatitude: 12.9, MinLongitude: 80.1, MaxLatitude: 13.2, MaxLongitude: 80.3}))
+	assert.ErrorIs(t, validateBoundingBox(BoundingBox{MinLatitude: 13.2, MinLongitude: 80.1, MaxLatitude: 12.9, MaxLongitude: 80.3}), ErrInvalidBoundingBox)
+	assert.ErrorIs(t, validateBoundingBox(BoundingBox{MinLatitude: -95, MinLongitude: 80.1, MaxLatitude: 12.9, MaxLongitude: 80.3}), ErrInvalidBoundingBox)
+}
@@ -0,0 +1,45 @@
+package shop
+
+import (
+	"shop-near-u/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyProfileChangesWithoutMove(t *testing.T) {
+	shop := &models.Shop{ID: 1, Name: "Old", Mobile: "111", Address: "MG Road", Latitude: 12.97, Longitude: 77.59}
+	name, mobile := "New", "222"
+
+	previous := applyProfileChanges(shop, &UpdateShopProfileDTORequest{Name: &name, Mobile: &mobile}, time.Now())
+
+	assert.Nil(t, previous)
+	assert.Equal(t, "New", shop.Name)
+	assert.Equal(t, "222", shop.Mobile)
+	assert.Nil(t, shop.RelocatedAt)
+	assert.Equal(t, 77.59, shop.Location.Lng)
+	assert.Equal(t, 12.97, shop.Location.Lat)
+}
+
+func TestApplyProfileChangesRecordsMove(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	shop := &models.Shop{ID: 7, Address: "MG Road", Latitude: 12.97, Longitude: 77.59}
+	address, lat, lon := "Indiranagar", 12.98, 77.64
+
+	previous := applyProfileChanges(shop, &UpdateShopProfileDTORequest{Address: &address, Latitude: &lat, Longitude: &lon}, now)
+
+	require.NotNil(t, previous)
+	assert.Equal(t, uint(7), previous.ShopID)
+	assert.Equal(t, "MG Road", previous.Address)
+	assert.Equal(t, 12.97, previous.Latitude)
+	assert.Equal(t, 77.59, previous.Longitude)
+	assert.Equal(t, now, previous.MovedAt)
+
+	assert.Equal(t, "Indiranagar", shop.Address)
+	assert.Equal(t, 77.64, shop.Location.Lng)
+	assert.Equal(t, 12.98, shop.Location.Lat)
+	require.NotNil(t, shop.RelocatedAt)
+	assert.Equal(t, now, *shop.RelocatedAt)
+}
@@ -3,6 +3,8 @@ package shop
 import (
 	"errors"
 	"shop-near-u/internal/models"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -11,24 +13,73 @@ type Repository struct {
 	DB *gorm.DB
 }
 
-func (r *Repository) FindNearbyShops(lat float64, lon float64, radius float64, limit int) ([]NearByShopsDTORespone, error) {
+func (r *Repository) FindNearbyShops(filter NearbyShopsFilter) ([]NearByShopsDTORespone, error) {
 	var shops []NearByShopsDTORespone
 
+	lon, lat := filter.Longitude, filter.Latitude
+	conditions := []string{"ST_DWithin(s.location, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography, ?)", "s.closed_at IS NULL AND s.suspended_at IS NULL"}
+	args := []interface{}{lon, lat, lon, lat, filter.Radius}
+
+	if filter.Type != "" {
+		conditions = append(conditions, "(LOWER(s.type) = LOWER(?) OR s.category_id IN ("+models.CategorySubtreeSQL+"))")
+		args = append(args, filter.Type, models.Slugify(filter.Type))
+	}
+	if filter.OpenNow {
+		conditions = append(conditions, models.ShopOpenNowSQL)
+	}
+	if filter.SupportsDelivery {
+		conditions = append(conditions, "s.supports_delivery")
+	}
+	if filter.MinSubscribers > 0 {
+		conditions = append(conditions, "s.subscriber_count >= ?")
+		args = append(args, filter.MinSubscribers)
+	}
+	if filter.CatalogID > 0 {
+		conditions = append(conditions, `EXISTS (
+            SELECT 1 FROM shop_products sp
+            WHERE sp.shop_id = s.id AND sp.catalog_id = ? AND sp.is_available AND sp.stock > 0
+        )`)
+		args = append(args, filter.CatalogID)
+	}
+	if filter.Category != "" {
+		conditions = append(conditions, `EXISTS (
+            SELECT 1 FROM shop_products sp
+            JOIN catalog_products cp ON cp.id = sp.catalog_id
+            WHERE sp.shop_id = s.id AND sp.is_available AND sp.stock > 0
+              AND (LOWER(cp.category) = LOWER(?) OR cp.category_id IN (`+models.CategorySubtreeSQL+`))
+        )`)
+		args = append(args, filter.Category, models.Slugify(filter.Category))
+	}
+
+	orderBy := "distance ASC"
+	switch filter.SortBy {
+	case SortByPopularity:
+		orderBy = "s.subscriber_count DESC, distance ASC"
+	case SortByName:
+		orderBy = "s.name ASC, distance ASC"
+	}
+	args = append(args, filter.Limit)
+
 	query := `
         SELECT 
-            id, 
-            name, 
-            address, 
-            latitude, 
-            longitude, 
-            ST_Distance(location, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography) AS distance
-        FROM shops
-        WHERE ST_DWithin(location, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography, ?)
-        ORDER BY distance
+            s.id, 
+            s.name, 
+            s.address, 
+            s.latitude, 
+            s.longitude, 
+            s.type,
+            s.supports_delivery,
+            s.subscriber_count,
+            s.location,
+            ST_Distance(s.location, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography) AS distance,
+            ` + models.ShopOpenNowSQL + ` AS is_open
+        FROM shops s
+        WHERE ` + strings.Join(conditions, "\n            AND ") + `
+        ORDER BY ` + orderBy + `
         LIMIT ?
     `
 
-	result := r.DB.Raw(query, lon, lat, lon, lat, radius, limit).Scan(&shops)
+	result := r.DB.Raw(query, args...).Scan(&shops)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -37,6 +88,136 @@ func (r *Repository) FindNearbyShops(lat float64, lon float64, radius float64, l
 	return shops, nil
 }
 
+// FindShopsInBoundingBox returns individual shops inside the viewport.
+func (r *Repository) FindShopsInBoundingBox(box BoundingBox, limit int) ([]MapShopDTOResponse, error) {
+	var shops []MapShopDTOResponse
+
+	query := `
+        SELECT
+            s.id,
+            s.name,
+            s.address,
+            s.type,
+            s.latitude,
+            s.longitude,
+            s.location,
+            ` + models.ShopOpenNowSQL + ` AS is_open
+        FROM shops s
+        WHERE s.location && ST_MakeEnvelope(?, ?, ?, ?, 4326)
+            AND s.closed_at IS NULL AND s.suspended_at IS NULL
+        ORDER BY s.subscriber_count DESC, s.id ASC
+        LIMIT ?
+    `
+
+	result := r.DB.Raw(query, box.MinLongitude, box.MinLatitude, box.MaxLongitude, box.MaxLatitude, limit).Scan(&shops)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return shops, nil
+}
+
+// ClusterShopsInBoundingBox snaps the shops inside the viewport to a grid of
+// gridSize degrees and returns one cluster per occupied cell, positioned at
+// the centroid of its shops.
+func (r *Repository) ClusterShopsInBoundingBox(box BoundingBox, gridSize float64) ([]MapClusterDTOResponse, error) {
+	var clusters []MapClusterDTOResponse
+
+	query := `
+        SELECT
+            COUNT(*) AS count,
+            ST_Y(ST_Centroid(ST_Collect(s.location))) AS latitude,
+            ST_X(ST_Centroid(ST_Collect(s.location))) AS longitude
+        FROM shops s
+        WHERE s.location && ST_MakeEnvelope(?, ?, ?, ?, 4326)
+            AND s.closed_at IS NULL AND s.suspended_at IS NULL
+        GROUP BY ST_SnapToGrid(s.location, ?)
+        ORDER BY count DESC
+    `
+
+	result := r.DB.Raw(query, box.MinLongitude, box.MinLatitude, box.MaxLongitude, box.MaxLatitude, gridSize).Scan(&clusters)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return clusters, nil
+}
+
+// SetServiceArea stores a GeoJSON polygon or multipolygon as the shop's
+// service area. Geometries PostGIS considers invalid, such as self-crossing
+// rings, are refused.
+func (r *Repository) SetServiceArea(shopID uint, geometry string) error {
+	query := `
+        UPDATE shops
+        SET service_area = ST_Multi(ST_SetSRID(ST_GeomFromGeoJSON(?), 4326))
+        WHERE id = ? AND ST_IsValid(ST_SetSRID(ST_GeomFromGeoJSON(?), 4326))
+    `
+
+	result := r.DB.Exec(query, geometry, shopID, geometry)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidServiceArea
+	}
+	return nil
+}
+
+func (r *Repository) ClearServiceArea(shopID uint) error {
+	return r.DB.Exec("UPDATE shops SET service_area = NULL WHERE id = ?", shopID).Error
+}
+
+// GetServiceArea returns the service area as GeoJSON, or nil when the shop
+// has none.
+func (r *Repository) GetServiceArea(shopID uint) (*string, error) {
+	var row struct {
+		ID          uint
+		ServiceArea *string
+	}
+
+	result := r.DB.Raw("SELECT id, ST_AsGeoJSON(service_area) AS service_area FROM shops WHERE id = ?", shopID).Scan(&row)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if row.ID == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return row.ServiceArea, nil
+}
+
+// FindShopsServingPoint lists shops whose service area polygon covers the point.
+func (r *Repository) FindShopsServingPoint(lat float64, lon float64, limit int) ([]NearByShopsDTORespone, error) {
+	var shops []NearByShopsDTORespone
+
+	query := `
+        SELECT
+            s.id,
+            s.name,
+            s.address,
+            s.latitude,
+            s.longitude,
+            s.type,
+            s.supports_delivery,
+            s.subscriber_count,
+            s.location,
+            ST_Distance(s.location, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography) AS distance,
+            ` + models.ShopOpenNowSQL + ` AS is_open
+        FROM shops s
+        WHERE s.service_area IS NOT NULL
+            AND s.closed_at IS NULL AND s.suspended_at IS NULL
+            AND ST_Covers(s.service_area, ST_SetSRID(ST_MakePoint(?, ?), 4326))
+        ORDER BY distance
+        LIMIT ?
+    `
+
+	result := r.DB.Raw(query, lon, lat, lon, lat, limit).Scan(&shops)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return shops, nil
+}
+
 func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{DB: db}
 }
@@ -56,8 +237,227 @@ func (r *Repository) FindByID(id uint) (*models.Shop, error) {
 	return &shop, result.Error
 }
 
-func (r *Repository) UpdateShopStatus(shopID uint, status bool) error {
-	return r.DB.Model(&models.Shop{}).Where("id = ?", shopID).Update("is_open", status).Error
+func (r *Repository) FindCategoryID(shopType string) (*uint, error) {
+	return models.ShopTypeCategoryID(r.DB, shopType)
+}
+
+// UpdateProfile writes the editable profile fields, including the coordinates
+// and PostGIS location in the same statement. When previous is set the shop
+// has moved and the old address is kept in its history.
+func (r *Repository) UpdateProfile(shop *models.Shop, previous *models.ShopAddressHistory) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Model(&models.Shop{}).Where("id = ?", shop.ID).Updates(map[string]interface{}{
+		"name":         shop.Name,
+		"owner_name":   shop.OwnerName,
+		"type":         shop.Type,
+		"category_id":  shop.CategoryID,
+		"mobile":       shop.Mobile,
+		"address":      shop.Address,
+		"latitude":     shop.Latitude,
+		"longitude":    shop.Longitude,
+		"location":     gorm.Expr("ST_SetSRID(ST_MakePoint(?, ?), 4326)", shop.Longitude, shop.Latitude),
+		"relocated_at": shop.RelocatedAt,
+	}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if previous != nil {
+		if err := tx.Create(previous).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (r *Repository) GetAddressHistory(shopID uint) ([]models.ShopAddressHistory, error) {
+	var history []models.ShopAddressHistory
+	err := r.DB.Where("shop_id = ?", shopID).Order("moved_at DESC").Find(&history).Error
+	return history, err
+}
+
+func (r *Repository) GetGallery(shopID uint) ([]models.ShopImage, error) {
+	var images []models.ShopImage
+	err := r.DB.Where("shop_id = ?", shopID).Order("position ASC, id ASC").Find(&images).Error
+	return images, err
+}
+
+func (r *Repository) UpdatePassword(shopID uint, password string) error {
+	return r.DB.Model(&models.Shop{}).Where("id = ?", shopID).Update("password", password).Error
+}
+
+// CloseShop permanently closes the shop. Its products are removed along with
+// the cart lines and reservations that reference them, open orders are
+// rejected, and subscriptions are archived so subscribers still see the
+// closed shop.
+func (r *Repository) CloseShop(shopID uint, now time.Time) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Model(&models.Shop{}).Where("id = ?", shopID).Updates(map[string]interface{}{
+		"closed_at":        now,
+		"is_open":          false,
+		"subscriber_count": 0,
+	}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Finished holds still reference the listings, so every reservation goes
+	// before the products do.
+	if err := tx.Where("shop_id = ?", shopID).Delete(&models.StockReservation{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Model(&models.Order{}).
+		Where("shop_id = ? AND status IN ?", shopID, []string{models.OrderStatusPlaced, models.OrderStatusAccepted, models.OrderStatusReady}).
+		Updates(map[string]interface{}{
+			"status":      models.OrderStatusRejected,
+			"reason":      "shop closed permanently",
+			"rejected_at": now,
+		}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("shop_product_id IN (?)", tx.Model(&models.ShopProduct{}).Select("id").Where("shop_id = ?", shopID)).
+		Delete(&models.CartItem{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("shop_id = ?", shopID).Delete(&models.ShopProduct{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Model(&models.ShopSubscription{}).
+		Where("shop_id = ? AND archived_at IS NULL", shopID).
+		Update("archived_at", now).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// UpdateShopStatus sets the manual open flag, which overrides the schedule
+// until the given time. A nil until clears the override.
+func (r *Repository) UpdateShopStatus(shopID uint, status bool, until *time.Time) error {
+	return r.DB.Model(&models.Shop{}).Where("id = ?", shopID).Updates(map[string]interface{}{
+		"is_open":               status,
+		"status_override_until": until,
+	}).Error
+}
+
+func (r *Repository) ClearStatusOverride(shopID uint) error {
+	return r.DB.Model(&models.Shop{}).Where("id = ?", shopID).Update("status_override_until", nil).Error
+}
+
+// ResolveOpenStatus computes the current open state for each of the shops.
+func (r *Repository) ResolveOpenStatus(shopIDs []uint) (map[uint]bool, error) {
+	var rows []struct {
+		ID     uint
+		IsOpen bool
+	}
+
+	status := make(map[uint]bool, len(shopIDs))
+	if len(shopIDs) == 0 {
+		return status, nil
+	}
+
+	query := `SELECT s.id, ` + models.ShopOpenNowSQL + ` AS is_open FROM shops s WHERE s.id IN ?`
+	if err := r.DB.Raw(query, shopIDs).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		status[row.ID] = row.IsOpen
+	}
+	return status, nil
+}
+
+func (r *Repository) GetOpeningHours(shopID uint) ([]models.ShopOpeningHours, error) {
+	var hours []models.ShopOpeningHours
+	result := r.DB.Where("shop_id = ?", shopID).Order("weekday ASC, opens_at ASC").Find(&hours)
+	return hours, result.Error
+}
+
+// ReplaceOpeningHours swaps the whole weekly schedule and the shop's time zone
+// in a single transaction.
+func (r *Repository) ReplaceOpeningHours(shopID uint, timeZone string, hours []models.ShopOpeningHours) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Model(&models.Shop{}).Where("id = ?", shopID).Update("time_zone", timeZone).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("shop_id = ?", shopID).Delete(&models.ShopOpeningHours{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(hours) > 0 {
+		if err := tx.Create(&hours).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// GetHoursExceptions lists exceptions on or after the given date.
+func (r *Repository) GetHoursExceptions(shopID uint, fromDate string) ([]models.ShopHoursException, error) {
+	var exceptions []models.ShopHoursException
+	result := r.DB.Where("shop_id = ? AND date >= ?", shopID, fromDate).Order("date ASC, opens_at ASC").Find(&exceptions)
+	return exceptions, result.Error
+}
+
+func (r *Repository) CreateHoursException(exception *models.ShopHoursException) error {
+	return r.DB.Create(exception).Error
+}
+
+func (r *Repository) DeleteHoursException(exceptionID uint, shopID uint) error {
+	result := r.DB.Where("id = ? AND shop_id = ?", exceptionID, shopID).Delete(&models.ShopHoursException{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
 func (r *Repository) SubscribeShop(shopID uint, userID uint) (uint, error) {
@@ -73,6 +473,16 @@ func (r *Repository) SubscribeShop(shopID uint, userID uint) (uint, error) {
 		return 0, tx.Error
 	}
 
+	var shop models.Shop
+	if err := tx.Select("id", "closed_at").First(&shop, shopID).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if shop.ClosedAt != nil {
+		tx.Rollback()
+		return 0, ErrShopClosed
+	}
+
 	// Check if already subscribed
 	var existingCount int64
 	if err := tx.Model(&models.ShopSubscription{}).Where("shop_id = ? AND user_id = ?", shopID, userID).Count(&existingCount).Error; err != nil {
@@ -97,7 +507,7 @@ func (r *Repository) SubscribeShop(shopID uint, userID uint) (uint, error) {
 
 	// Recalculate subscriber count
 	var subscriberCount int64
-	if err := tx.Model(&models.ShopSubscription{}).Where("shop_id = ?", shopID).Count(&subscriberCount).Error; err != nil {
+	if err := tx.Model(&models.ShopSubscription{}).Where("shop_id = ? AND archived_at IS NULL", shopID).Count(&subscriberCount).Error; err != nil {
 		tx.Rollback()
 		return 0, err
 	}
@@ -149,7 +559,7 @@ func (r *Repository) UnsubscribeShop(shopID uint, userID uint) (uint, error) {
 
 	// Recalculate subscriber count
 	var subscriberCount int64
-	if err := tx.Model(&models.ShopSubscription{}).Where("shop_id = ?", shopID).Count(&subscriberCount).Error; err != nil {
+	if err := tx.Model(&models.ShopSubscription{}).Where("shop_id = ? AND archived_at IS NULL", shopID).Count(&subscriberCount).Error; err != nil {
 		tx.Rollback()
 		return 0, err
 	}
@@ -210,3 +620,47 @@ func (r *Repository) GetUserSubscribedShops(userID uint) ([]models.Shop, error)
 
 	return shops, nil
 }
+
+func (r *Repository) GetDeliveryFeeTiers(shopID uint) ([]models.DeliveryFeeTier, error) {
+	var tiers []models.DeliveryFeeTier
+	result := r.DB.Where("shop_id = ?", shopID).Order("max_distance ASC").Find(&tiers)
+	return tiers, result.Error
+}
+
+// UpdateDeliverySettings stores the delivery flags on the shop and replaces its
+// fee schedule in a single transaction.
+func (r *Repository) UpdateDeliverySettings(shop *models.Shop, tiers []models.DeliveryFeeTier) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Model(&models.Shop{}).Where("id = ?", shop.ID).Updates(map[string]interface{}{
+		"supports_delivery":   shop.SupportsDelivery,
+		"delivery_radius":     shop.DeliveryRadius,
+		"free_delivery_above": shop.FreeDeliveryAbove,
+	}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("shop_id = ?", shop.ID).Delete(&models.DeliveryFeeTier{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(tiers) > 0 {
+		if err := tx.Create(&tiers).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
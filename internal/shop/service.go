@@ -1,40 +1,92 @@
 package shop
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"shop-near-u/internal/geocoding"
 	"shop-near-u/internal/models"
 	"shop-near-u/internal/utils"
+	"sort"
+	"time"
 
 	"github.com/restayway/gogis"
 )
 
+var (
+	ErrDeliveryRadiusRequired = errors.New("delivery radius is required when delivery is supported")
+	ErrInvalidTimeZone        = errors.New("invalid time zone")
+	ErrInvalidOpeningHours    = errors.New("invalid opening hours")
+	ErrInvalidDate            = errors.New("invalid date, expected YYYY-MM-DD")
+	ErrInvalidBoundingBox     = errors.New("invalid bounding box")
+	ErrAddressNotGeocoded     = errors.New("could not find coordinates for the address, please provide latitude and longitude")
+	ErrShopClosed             = errors.New("shop closed permanently")
+	ErrShopSuspended          = errors.New("shop suspended")
+	ErrIncompleteCoordinates  = errors.New("latitude and longitude must be provided together")
+	ErrLocationRequired       = errors.New("lat and lon are required unless you are signed in with a default address")
+)
+
+const (
+	// clusterMaxZoom is the last zoom level at which shops are clustered;
+	// closer in, the map gets individual shops.
+	clusterMaxZoom = 13
+	maxMapZoom     = 22
+	maxMapShops    = 500
+	// clusterCellsPerTile controls how many grid cells span one map tile.
+	clusterCellsPerTile = 4
+)
+
 type Service struct {
 	repository *Repository
+	geocoder   geocoding.Geocoder
 }
 
-func NewService(r *Repository) *Service {
-	return &Service{repository: r}
+func NewService(r *Repository, g geocoding.Geocoder) *Service {
+	return &Service{repository: r, geocoder: g}
 }
 
-func (s *Service) RegisterShop(registerDTO *ShopRegisterDTORequest) (*models.Shop, error) {
+func (s *Service) RegisterShop(ctx context.Context, registerDTO *ShopRegisterDTORequest) (*models.Shop, error) {
+	if registerDTO.SupportsDelivery && registerDTO.DeliveryRadius <= 0 {
+		return nil, ErrDeliveryRadiusRequired
+	}
+
+	if (registerDTO.Latitude == nil) != (registerDTO.Longitude == nil) {
+		return nil, ErrIncompleteCoordinates
+	}
+
+	if registerDTO.Latitude == nil {
+		lat, lon, err := s.resolveAddress(ctx, registerDTO.Address)
+		if err != nil {
+			return nil, err
+		}
+		registerDTO.Latitude, registerDTO.Longitude = &lat, &lon
+	}
 
 	password, err := utils.HashPassword(registerDTO.Password)
 	if err != nil {
 		return nil, err
 	}
+	categoryID, err := s.repository.FindCategoryID(registerDTO.Type)
+	if err != nil {
+		return nil, err
+	}
 	shop := &models.Shop{
-		Name:      registerDTO.Name,
-		OwnerName: registerDTO.OwnerName,
-		Type:      registerDTO.Type,
-		Password:  password,
-		Email:     registerDTO.Email,
-		Mobile:    registerDTO.Mobile,
-		Address:   registerDTO.Address,
-		Latitude:  registerDTO.Latitude,
-		Longitude: registerDTO.Longitude,
+		Name:       registerDTO.Name,
+		OwnerName:  registerDTO.OwnerName,
+		Type:       registerDTO.Type,
+		CategoryID: categoryID,
+		Password:   password,
+		Email:      registerDTO.Email,
+		Mobile:     registerDTO.Mobile,
+		Address:    registerDTO.Address,
+		Latitude:   *registerDTO.Latitude,
+		Longitude:  *registerDTO.Longitude,
 		Location: gogis.Point{
-			Lng: registerDTO.Longitude,
-			Lat: registerDTO.Latitude,
+			Lng: *registerDTO.Longitude,
+			Lat: *registerDTO.Latitude,
 		},
+		SupportsDelivery: registerDTO.SupportsDelivery,
+		DeliveryRadius:   registerDTO.DeliveryRadius,
 	}
 
 	if err := s.repository.Create(shop); err != nil {
@@ -44,6 +96,96 @@ func (s *Service) RegisterShop(registerDTO *ShopRegisterDTORequest) (*models.Sho
 	return shop, nil
 }
 
+// resolveAddress geocodes a free-text address into latitude and longitude.
+func (s *Service) resolveAddress(ctx context.Context, address string) (float64, float64, error) {
+	result, err := s.geocoder.Geocode(ctx, address)
+	if err != nil {
+		if errors.Is(err, geocoding.ErrNotFound) {
+			return 0, 0, ErrAddressNotGeocoded
+		}
+		return 0, 0, err
+	}
+	return result.Latitude, result.Longitude, nil
+}
+
+// UpdateProfile applies the requested profile changes. A new address without
+// coordinates is geocoded, and a change of address or coordinates is recorded
+// as a move.
+func (s *Service) UpdateProfile(ctx context.Context, shop *models.Shop, dto *UpdateShopProfileDTORequest) (*models.Shop, error) {
+	if (dto.Latitude == nil) != (dto.Longitude == nil) {
+		return nil, ErrIncompleteCoordinates
+	}
+
+	if dto.Address != nil && dto.Latitude == nil && *dto.Address != shop.Address {
+		lat, lon, err := s.resolveAddress(ctx, *dto.Address)
+		if err != nil {
+			return nil, err
+		}
+		dto.Latitude, dto.Longitude = &lat, &lon
+	}
+
+	if dto.Type != nil {
+		categoryID, err := s.repository.FindCategoryID(*dto.Type)
+		if err != nil {
+			return nil, err
+		}
+		shop.CategoryID = categoryID
+	}
+
+	previous := applyProfileChanges(shop, dto, time.Now().UTC())
+	if err := s.repository.UpdateProfile(shop, previous); err != nil {
+		return nil, err
+	}
+	return shop, nil
+}
+
+func (s *Service) GetAddressHistory(shopID uint) ([]models.ShopAddressHistory, error) {
+	if _, err := s.repository.FindByID(shopID); err != nil {
+		return nil, err
+	}
+	return s.repository.GetAddressHistory(shopID)
+}
+
+// applyProfileChanges copies the present fields onto shop. If the address or
+// coordinates changed it returns the address being left behind.
+func applyProfileChanges(shop *models.Shop, dto *UpdateShopProfileDTORequest, now time.Time) *models.ShopAddressHistory {
+	if dto.Name != nil {
+		shop.Name = *dto.Name
+	}
+	if dto.OwnerName != nil {
+		shop.OwnerName = *dto.OwnerName
+	}
+	if dto.Type != nil {
+		shop.Type = *dto.Type
+	}
+	if dto.Mobile != nil {
+		shop.Mobile = *dto.Mobile
+	}
+
+	previous := &models.ShopAddressHistory{
+		ShopID:    shop.ID,
+		Address:   shop.Address,
+		Latitude:  shop.Latitude,
+		Longitude: shop.Longitude,
+		MovedAt:   now,
+	}
+
+	if dto.Address != nil {
+		shop.Address = *dto.Address
+	}
+	if dto.Latitude != nil && dto.Longitude != nil {
+		shop.Latitude = *dto.Latitude
+		shop.Longitude = *dto.Longitude
+	}
+	shop.Location = gogis.Point{Lng: shop.Longitude, Lat: shop.Latitude}
+
+	if shop.Address == previous.Address && shop.Latitude == previous.Latitude && shop.Longitude == previous.Longitude {
+		return nil
+	}
+	shop.RelocatedAt = &now
+	return previous
+}
+
 func (s *Service) AuthenticateShop(request *ShopLoginDTORequest) (*models.Shop, error) {
 	shop, err := s.repository.FindByEmail(request.Email)
 	if err != nil {
@@ -58,16 +200,45 @@ func (s *Service) AuthenticateShop(request *ShopLoginDTORequest) (*models.Shop,
 		return nil, nil
 	}
 
+	if shop.ClosedAt != nil {
+		return nil, ErrShopClosed
+	}
+	if shop.SuspendedAt != nil {
+		return nil, ErrShopSuspended
+	}
+
 	return shop, nil
 }
 
+func (s *Service) ChangePassword(shopID uint, oldPassword string, newPassword string) error {
+	shop, err := s.repository.FindByID(shopID)
+	if err != nil {
+		return err
+	}
+
+	if err := utils.CheckPasswordHash(oldPassword, shop.Password); err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	return s.repository.UpdatePassword(shopID, hashedPassword)
+}
+
+func (s *Service) CloseShop(shopID uint) error {
+	return s.repository.CloseShop(shopID, time.Now().UTC())
+}
+
 func (s *Service) GetShopByID(shopID uint) (*models.Shop, error) {
 	return s.repository.FindByID(shopID)
 
 }
 
-func (s *Service) GetNearbyShops(lat float64, lon float64, radius float64, limit int) ([]NearByShopsDTORespone, error) {
-	shops, err := s.repository.FindNearbyShops(lat, lon, radius, limit)
+func (s *Service) GetNearbyShops(filter NearbyShopsFilter) ([]NearByShopsDTORespone, error) {
+	shops, err := s.repository.FindNearbyShops(filter)
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +246,56 @@ func (s *Service) GetNearbyShops(lat float64, lon float64, radius float64, limit
 	return shops, nil
 }
 
+// GetShopsInViewport returns individual shops when zoomed in and grid
+// clusters when zoomed out.
+func (s *Service) GetShopsInViewport(box BoundingBox, zoom int) (*MapViewportDTOResponse, error) {
+	if err := validateBoundingBox(box); err != nil {
+		return nil, err
+	}
+	if zoom < 0 || zoom > maxMapZoom {
+		return nil, fmt.Errorf("%w: zoom must be between 0 and %d", ErrInvalidBoundingBox, maxMapZoom)
+	}
+
+	response := &MapViewportDTOResponse{Zoom: zoom}
+
+	if zoom > clusterMaxZoom {
+		shops, err := s.repository.FindShopsInBoundingBox(box, maxMapShops)
+		if err != nil {
+			return nil, err
+		}
+		response.Shops = shops
+		return response, nil
+	}
+
+	clusters, err := s.repository.ClusterShopsInBoundingBox(box, clusterGridSize(zoom))
+	if err != nil {
+		return nil, err
+	}
+	response.Clustered = true
+	response.Clusters = clusters
+	return response, nil
+}
+
+func (s *Service) SetServiceArea(shopID uint, raw []byte) error {
+	geometry, err := parseServiceArea(raw)
+	if err != nil {
+		return err
+	}
+	return s.repository.SetServiceArea(shopID, geometry)
+}
+
+func (s *Service) ClearServiceArea(shopID uint) error {
+	return s.repository.ClearServiceArea(shopID)
+}
+
+func (s *Service) GetServiceArea(shopID uint) (*string, error) {
+	return s.repository.GetServiceArea(shopID)
+}
+
+func (s *Service) GetShopsServingPoint(lat float64, lon float64, limit int) ([]NearByShopsDTORespone, error) {
+	return s.repository.FindShopsServingPoint(lat, lon, limit)
+}
+
 func (s *Service) SubscribeShop(userID uint, shopID uint) (uint, error) {
 	subscriberCount, err := s.repository.SubscribeShop(shopID, userID)
 	if err != nil {
@@ -83,8 +304,35 @@ func (s *Service) SubscribeShop(userID uint, shopID uint) (uint, error) {
 	return subscriberCount, nil
 }
 
-func (s *Service) UpdateShopStatus(shopID uint, status bool) error {
-	return s.repository.UpdateShopStatus(shopID, status)
+// UpdateShopStatus manually opens or closes the shop until the given time,
+// or until the next local midnight when until is nil.
+func (s *Service) UpdateShopStatus(shopID uint, status bool, until *time.Time) error {
+	if until == nil {
+		shop, err := s.repository.FindByID(shopID)
+		if err != nil {
+			return err
+		}
+		midnight := nextLocalMidnight(shop.TimeZone, time.Now())
+		until = &midnight
+	}
+	return s.repository.UpdateShopStatus(shopID, status, until)
+}
+
+// ClearStatusOverride hands the open state back to the schedule.
+func (s *Service) ClearStatusOverride(shopID uint) error {
+	return s.repository.ClearStatusOverride(shopID)
+}
+
+func (s *Service) IsShopOpen(shopID uint) (bool, error) {
+	if _, err := s.repository.FindByID(shopID); err != nil {
+		return false, err
+	}
+
+	status, err := s.repository.ResolveOpenStatus([]uint{shopID})
+	if err != nil {
+		return false, err
+	}
+	return status[shopID], nil
 }
 
 func (s *Service) UnsubscribeShop(userID uint, shopID uint) (uint, error) {
@@ -95,14 +343,297 @@ func (s *Service) UnsubscribeShop(userID uint, shopID uint) (uint, error) {
 	return subscriberCount, nil
 }
 
+// GetGallery returns the shop's gallery pictures with their variant URLs.
+func (s *Service) GetGallery(shopID uint) ([]ShopImageDTOResponse, error) {
+	images, err := s.repository.GetGallery(shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	gallery := make([]ShopImageDTOResponse, 0, len(images))
+	for _, image := range images {
+		gallery = append(gallery, ShopImageDTOResponse{
+			ID:      image.ID,
+			Caption: image.Caption,
+			Images:  image.Images,
+		})
+	}
+	return gallery, nil
+}
+
 func (s *Service) GetShopDetails(shopID uint, userID uint) (*models.Shop, bool, error) {
 	shop, isSubscribed, err := s.repository.GetShopDetails(shopID, userID)
 	if err != nil {
 		return nil, false, err
 	}
-	return shop, isSubscribed, nil
+
+	shops := []models.Shop{*shop}
+	if err := s.applyOpenStatus(shops); err != nil {
+		return nil, false, err
+	}
+	return &shops[0], isSubscribed, nil
 }
 
 func (s *Service) GetUserSubscribedShops(userID uint) ([]models.Shop, error) {
-	return s.repository.GetUserSubscribedShops(userID)
+	shops, err := s.repository.GetUserSubscribedShops(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyOpenStatus(shops); err != nil {
+		return nil, err
+	}
+	return shops, nil
+}
+
+// applyOpenStatus replaces the stored IsOpen flag with the schedule-derived state.
+func (s *Service) applyOpenStatus(shops []models.Shop) error {
+	ids := make([]uint, 0, len(shops))
+	for _, shop := range shops {
+		ids = append(ids, shop.ID)
+	}
+
+	status, err := s.repository.ResolveOpenStatus(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range shops {
+		shops[i].IsOpen = status[shops[i].ID]
+	}
+	return nil
+}
+
+func (s *Service) GetDeliverySettings(shopID uint) (*DeliverySettingsDTOResponse, error) {
+	shop, err := s.repository.FindByID(shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	tiers, err := s.repository.GetDeliveryFeeTiers(shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDeliverySettingsResponse(shop, tiers), nil
+}
+
+func (s *Service) UpdateDeliverySettings(shopID uint, dto *UpdateDeliverySettingsDTORequest) (*DeliverySettingsDTOResponse, error) {
+	if dto.SupportsDelivery && dto.DeliveryRadius <= 0 {
+		return nil, ErrDeliveryRadiusRequired
+	}
+
+	shop := &models.Shop{
+		ID:                shopID,
+		SupportsDelivery:  dto.SupportsDelivery,
+		DeliveryRadius:    dto.DeliveryRadius,
+		FreeDeliveryAbove: dto.FreeDeliveryAbove,
+	}
+
+	tiers := make([]models.DeliveryFeeTier, 0, len(dto.FeeTiers))
+	for _, tier := range dto.FeeTiers {
+		tiers = append(tiers, models.DeliveryFeeTier{
+			ShopID:      shopID,
+			MaxDistance: tier.MaxDistance,
+			Fee:         tier.Fee,
+		})
+	}
+
+	if err := s.repository.UpdateDeliverySettings(shop, tiers); err != nil {
+		return nil, err
+	}
+
+	return toDeliverySettingsResponse(shop, tiers), nil
+}
+
+func toDeliverySettingsResponse(shop *models.Shop, tiers []models.DeliveryFeeTier) *DeliverySettingsDTOResponse {
+	response := &DeliverySettingsDTOResponse{
+		SupportsDelivery:  shop.SupportsDelivery,
+		DeliveryRadius:    shop.DeliveryRadius,
+		FreeDeliveryAbove: shop.FreeDeliveryAbove,
+		FeeTiers:          []DeliveryFeeTierDTO{},
+	}
+	for _, tier := range tiers {
+		response.FeeTiers = append(response.FeeTiers, DeliveryFeeTierDTO{
+			MaxDistance: tier.MaxDistance,
+			Fee:         tier.Fee,
+		})
+	}
+	return response
+}
+
+func (s *Service) GetOpeningHours(shopID uint) (*OpeningHoursDTOResponse, error) {
+	shop, err := s.repository.FindByID(shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	isOpen, err := s.IsShopOpen(shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	hours, err := s.repository.GetOpeningHours(shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := localDate(shop.TimeZone, time.Now())
+	exceptions, err := s.repository.GetHoursExceptions(shopID, today)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrideUntil *time.Time
+	if shop.StatusOverrideUntil != nil && shop.StatusOverrideUntil.After(time.Now()) {
+		overrideUntil = shop.StatusOverrideUntil
+	}
+
+	return &OpeningHoursDTOResponse{
+		TimeZone:            shop.TimeZone,
+		IsOpen:              isOpen,
+		StatusOverrideUntil: overrideUntil,
+		Hours:               hours,
+		Exceptions:          exceptions,
+	}, nil
+}
+
+func (s *Service) UpdateOpeningHours(shopID uint, dto *UpdateOpeningHoursDTORequest) error {
+	if _, err := time.LoadLocation(dto.TimeZone); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidTimeZone, dto.TimeZone)
+	}
+
+	hours := make([]models.ShopOpeningHours, 0, len(dto.Hours))
+	for _, interval := range dto.Hours {
+		opensAt, closesAt, err := parseInterval(interval.OpensAt, interval.ClosesAt)
+		if err != nil {
+			return err
+		}
+		hours = append(hours, models.ShopOpeningHours{
+			ShopID:   shopID,
+			Weekday:  interval.Weekday,
+			OpensAt:  opensAt,
+			ClosesAt: closesAt,
+		})
+	}
+
+	if err := checkOverlaps(hours); err != nil {
+		return err
+	}
+
+	return s.repository.ReplaceOpeningHours(shopID, dto.TimeZone, hours)
+}
+
+func (s *Service) AddHoursException(shopID uint, dto *HoursExceptionDTORequest) (*models.ShopHoursException, error) {
+	if _, err := time.Parse("2006-01-02", dto.Date); err != nil {
+		return nil, ErrInvalidDate
+	}
+
+	exception := &models.ShopHoursException{
+		ShopID: shopID,
+		Date:   dto.Date,
+		Closed: dto.Closed,
+		Note:   dto.Note,
+	}
+
+	if !dto.Closed {
+		opensAt, closesAt, err := parseInterval(dto.OpensAt, dto.ClosesAt)
+		if err != nil {
+			return nil, err
+		}
+		exception.OpensAt = opensAt
+		exception.ClosesAt = closesAt
+	}
+
+	if err := s.repository.CreateHoursException(exception); err != nil {
+		return nil, err
+	}
+	return exception, nil
+}
+
+func (s *Service) DeleteHoursException(exceptionID uint, shopID uint) error {
+	return s.repository.DeleteHoursException(exceptionID, shopID)
+}
+
+// parseClock normalises an "HH:MM" time of day. "24:00" is accepted so an
+// interval can run to the end of the day.
+func parseClock(value string) (string, error) {
+	if value == "24:00" {
+		return value, nil
+	}
+
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q is not HH:MM", ErrInvalidOpeningHours, value)
+	}
+	return parsed.Format("15:04"), nil
+}
+
+// parseInterval validates an open interval within a single day. Overnight
+// hours are written as two intervals, one on each day.
+func parseInterval(opens string, closes string) (string, string, error) {
+	opensAt, err := parseClock(opens)
+	if err != nil {
+		return "", "", err
+	}
+	closesAt, err := parseClock(closes)
+	if err != nil {
+		return "", "", err
+	}
+	if opensAt >= closesAt {
+		return "", "", fmt.Errorf("%w: %s must be before %s", ErrInvalidOpeningHours, opensAt, closesAt)
+	}
+	return opensAt, closesAt, nil
+}
+
+func checkOverlaps(hours []models.ShopOpeningHours) error {
+	sorted := make([]models.ShopOpeningHours, len(hours))
+	copy(sorted, hours)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Weekday != sorted[j].Weekday {
+			return sorted[i].Weekday < sorted[j].Weekday
+		}
+		return sorted[i].OpensAt < sorted[j].OpensAt
+	})
+
+	for i := 1; i < len(sorted); i++ {
+		prev, curr := sorted[i-1], sorted[i]
+		if prev.Weekday == curr.Weekday && curr.OpensAt < prev.ClosesAt {
+			return fmt.Errorf("%w: intervals overlap on weekday %d", ErrInvalidOpeningHours, curr.Weekday)
+		}
+	}
+	return nil
+}
+
+func shopLocation(timeZone string) *time.Location {
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+func localDate(timeZone string, now time.Time) string {
+	return now.In(shopLocation(timeZone)).Format("2006-01-02")
+}
+
+func nextLocalMidnight(timeZone string, now time.Time) time.Time {
+	local := now.In(shopLocation(timeZone))
+	return time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, local.Location())
+}
+
+func validateBoundingBox(box BoundingBox) error {
+	if box.MinLatitude < -90 || box.MaxLatitude > 90 || box.MinLongitude < -180 || box.MaxLongitude > 180 {
+		return fmt.Errorf("%w: coordinates out of range", ErrInvalidBoundingBox)
+	}
+	if box.MinLatitude >= box.MaxLatitude || box.MinLongitude >= box.MaxLongitude {
+		return fmt.Errorf("%w: min must be less than max", ErrInvalidBoundingBox)
+	}
+	return nil
+}
+
+// clusterGridSize is the grid cell size in degrees for a web-map zoom level.
+// A tile spans 360/2^zoom degrees of longitude.
+func clusterGridSize(zoom int) float64 {
+	return 360 / float64(uint(1)<<uint(zoom)) / clusterCellsPerTile
 }
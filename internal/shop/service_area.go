@@ -0,0 +1,90 @@
+package shop
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidServiceArea = errors.New("invalid service area")
+
+type geoJSONObject struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    *geoJSONObject  `json:"geometry"`
+}
+
+// parseServiceArea accepts a GeoJSON Polygon or MultiPolygon, bare or wrapped
+// in a Feature, checks its rings and returns the geometry JSON for PostGIS.
+func parseServiceArea(raw []byte) (string, error) {
+	var object geoJSONObject
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidServiceArea, err)
+	}
+
+	if object.Type == "Feature" {
+		if object.Geometry == nil {
+			return "", fmt.Errorf("%w: feature has no geometry", ErrInvalidServiceArea)
+		}
+		object = *object.Geometry
+	}
+
+	var polygons [][][][]float64
+	switch object.Type {
+	case "Polygon":
+		var polygon [][][]float64
+		if err := json.Unmarshal(object.Coordinates, &polygon); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidServiceArea, err)
+		}
+		polygons = [][][][]float64{polygon}
+	case "MultiPolygon":
+		if err := json.Unmarshal(object.Coordinates, &polygons); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidServiceArea, err)
+		}
+	default:
+		return "", fmt.Errorf("%w: expected Polygon or MultiPolygon, got %q", ErrInvalidServiceArea, object.Type)
+	}
+
+	if len(polygons) == 0 {
+		return "", fmt.Errorf("%w: no polygons", ErrInvalidServiceArea)
+	}
+	for _, polygon := range polygons {
+		if err := validatePolygon(polygon); err != nil {
+			return "", err
+		}
+	}
+
+	geometry, err := json.Marshal(struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}{object.Type, object.Coordinates})
+	if err != nil {
+		return "", err
+	}
+	return string(geometry), nil
+}
+
+func validatePolygon(polygon [][][]float64) error {
+	if len(polygon) == 0 {
+		return fmt.Errorf("%w: polygon has no rings", ErrInvalidServiceArea)
+	}
+
+	for _, ring := range polygon {
+		if len(ring) < 4 {
+			return fmt.Errorf("%w: a ring needs at least four positions", ErrInvalidServiceArea)
+		}
+		for _, position := range ring {
+			if len(position) < 2 {
+				return fmt.Errorf("%w: positions need longitude and latitude", ErrInvalidServiceArea)
+			}
+			if position[0] < -180 || position[0] > 180 || position[1] < -90 || position[1] > 90 {
+				return fmt.Errorf("%w: position out of range", ErrInvalidServiceArea)
+			}
+		}
+		first, last := ring[0], ring[len(ring)-1]
+		if first[0] != last[0] || first[1] != last[1] {
+			return fmt.Errorf("%w: rings must be closed", ErrInvalidServiceArea)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package shop
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxServiceAreaBytes caps uploaded GeoJSON so a huge polygon cannot stall the request.
+const maxServiceAreaBytes = 1 << 20
+
+func (ctrl *Controller) SetServiceArea(c *gin.Context) {
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, 401, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, 500, "failed to parse shop data")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxServiceAreaBytes+1))
+	if err != nil {
+		utils.ErrorResponseSimple(c, 400, err.Error())
+		return
+	}
+	if len(body) > maxServiceAreaBytes {
+		utils.ErrorResponseSimple(c, 413, "service area is too large")
+		return
+	}
+
+	if err := ctrl.shopService.SetServiceArea(shop.ID, body); err != nil {
+		if errors.Is(err, ErrInvalidServiceArea) {
+			utils.ErrorResponseSimple(c, 400, err.Error())
+			return
+		}
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Service area updated successfully", nil)
+}
+
+func (ctrl *Controller) ClearServiceArea(c *gin.Context) {
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, 401, "unauthorized")
+		return
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, 500, "failed to parse shop data")
+		return
+	}
+
+	if err := ctrl.shopService.ClearServiceArea(shop.ID); err != nil {
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Service area removed successfully", nil)
+}
+
+func (ctrl *Controller) GetServiceArea(c *gin.Context) {
+	shopID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, 400, "invalid shop ID")
+		return
+	}
+
+	area, err := ctrl.shopService.GetServiceArea(shopID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponseSimple(c, 404, "shop not found")
+			return
+		}
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	if area == nil {
+		utils.SuccessResponse(c, http.StatusOK, "Shop has no service area", nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Service area retrieved successfully", json.RawMessage(*area))
+}
+
+func (ctrl *Controller) GetShopsServingPoint(c *gin.Context) {
+	lat, err := utils.ParseFloatParam(c.Query("lat"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, 400, "invalid latitude")
+		return
+	}
+
+	lon, err := utils.ParseFloatParam(c.Query("lon"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, 400, "invalid longitude")
+		return
+	}
+
+	limit, err := utils.ParseIntParam(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, 400, "invalid limit")
+		return
+	}
+
+	shops, err := ctrl.shopService.GetShopsServingPoint(lat, lon, limit)
+	if err != nil {
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
+	if utils.WantsGeoJSON(c) {
+		utils.SuccessResponse(c, http.StatusOK, "Serving shops retrieved successfully", nearbyShopsToGeoJSON(shops))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Serving shops retrieved successfully", shops)
+}
@@ -0,0 +1,43 @@
+package shop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServiceAreaPolygonFeature(t *testing.T) {
+	raw := `{
+		"type": "Feature",
+		"properties": {},
+		"geometry": {
+			"type": "Polygon",
+			"coordinates": [[[80.20, 13.00], [80.30, 13.00], [80.30, 13.10], [80.20, 13.10], [80.20, 13.00]]]
+		}
+	}`
+
+	geometry, err := parseServiceArea([]byte(raw))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "Polygon",
+		"coordinates": [[[80.20, 13.00], [80.30, 13.00], [80.30, 13.10], [80.20, 13.10], [80.20, 13.00]]]
+	}`, geometry)
+}
+
+func TestParseServiceAreaRejectsInvalidShapes(t *testing.T) {
+	cases := map[string]string{
+		"point":        `{"type": "Point", "coordinates": [80.2, 13.0]}`,
+		"open ring":    `{"type": "Polygon", "coordinates": [[[80.2, 13.0], [80.3, 13.0], [80.3, 13.1], [80.2, 13.1]]]}`,
+		"short ring":   `{"type": "Polygon", "coordinates": [[[80.2, 13.0], [80.3, 13.0], [80.2, 13.0]]]}`,
+		"out of range": `{"type": "MultiPolygon", "coordinates": [[[[200, 13.0], [80.3, 13.0], [80.3, 13.1], [200, 13.0]]]]}`,
+		"not json":     `polygon`,
+	}
+
+	for name, raw := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseServiceArea([]byte(raw))
+			assert.ErrorIs(t, err, ErrInvalidServiceArea)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package shop
 
 import (
+	"errors"
 	"net/http"
 	"shop-near-u/internal/models"
 	"shop-near-u/internal/utils"
@@ -33,6 +34,10 @@ func (ctrl *Controller) SubscribeShop(c *gin.Context) {
 			utils.ErrorResponseSimple(c, 400, "User is already subscribed to this shop")
 			return
 		}
+		if errors.Is(err, ErrShopClosed) {
+			utils.ErrorResponseSimple(c, 410, err.Error())
+			return
+		}
 		utils.ErrorResponseSimple(c, 500, err.Error())
 		return
 	}
@@ -104,11 +109,21 @@ func (ctrl *Controller) GetShopDetails(c *gin.Context) {
 		return
 	}
 
+	gallery, err := ctrl.shopService.GetGallery(shop.ID)
+	if err != nil {
+		utils.ErrorResponseSimple(c, 500, err.Error())
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Shop details retrieved successfully", GetShopDetailsDTOResponse{
 		ID:              shop.ID,
 		Name:            shop.Name,
 		SubscriberCount: shop.SubscriberCount,
 		IsSubscribed:    isSubscribed,
+		IsOpen:          shop.IsOpen,
+
+		ClosedPermanently: shop.ClosedAt != nil,
+		Gallery:           gallery,
 	})
 }
 
@@ -128,21 +143,33 @@ func (ctrl *Controller) GetUserSubscriptions(c *gin.Context) {
 		return
 	}
 
+	if utils.WantsGeoJSON(c) {
+		utils.SuccessResponse(c, http.StatusOK, "User subscriptions retrieved successfully", subscribedShopsToGeoJSON(shops))
+		return
+	}
+
 	// Convert to response DTOs
 	var response []SubscribedShopDTOResponse
 	for _, shop := range shops {
-		response = append(response, SubscribedShopDTOResponse{
-			ID:              shop.ID,
-			Name:            shop.Name,
-			OwnerName:       shop.OwnerName,
-			Type:            shop.Type,
-			Address:         shop.Address,
-			Latitude:        shop.Latitude,
-			Longitude:       shop.Longitude,
-			SubscriberCount: shop.SubscriberCount,
-			IsOpen:          shop.IsOpen,
-		})
+		response = append(response, toSubscribedShopResponse(shop))
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "User subscriptions retrieved successfully", response)
 }
+
+func toSubscribedShopResponse(shop models.Shop) SubscribedShopDTOResponse {
+	return SubscribedShopDTOResponse{
+		ID:              shop.ID,
+		Name:            shop.Name,
+		OwnerName:       shop.OwnerName,
+		Type:            shop.Type,
+		Address:         shop.Address,
+		Latitude:        shop.Latitude,
+		Longitude:       shop.Longitude,
+		SubscriberCount: shop.SubscriberCount,
+		IsOpen:          shop.IsOpen,
+
+		RelocatedAt:       shop.RelocatedAt,
+		ClosedPermanently: shop.ClosedAt != nil,
+	}
+}
@@ -0,0 +1,57 @@
+package staff
+
+import "time"
+
+type InviteStaffDTORequest struct {
+	Email       string   `json:"email" binding:"required,email"`
+	Name        string   `json:"name" binding:"max=100"`
+	Permissions []string `json:"permissions" binding:"required,min=1,dive,oneof=manage_products toggle_status view_orders"`
+}
+
+type UpdateStaffPermissionsDTORequest struct {
+	Permissions []string `json:"permissions" binding:"required,min=1,dive,oneof=manage_products toggle_status view_orders"`
+}
+
+// AcceptInviteDTORequest turns an invite into a working account. Name
+// replaces the one the owner entered when given.
+type AcceptInviteDTORequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Token    string `json:"token" binding:"required"`
+	Name     string `json:"name" binding:"max=100"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// StaffLoginDTORequest signs a staff member in. ShopID is only needed when
+// the same email works at more than one shop.
+type StaffLoginDTORequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+	ShopID   uint   `json:"shop_id"`
+}
+
+const (
+	StaffStatusInvited = "invited"
+	StaffStatusActive  = "active"
+)
+
+type StaffDTOResponse struct {
+	ID              uint       `json:"id"`
+	ShopID          uint       `json:"shop_id"`
+	Email           string     `json:"email"`
+	Name            string     `json:"name"`
+	Permissions     []string   `json:"permissions"`
+	Status          string     `json:"status"`
+	InviteExpiresAt *time.Time `json:"invite_expires_at,omitempty"`
+}
+
+// InviteStaffDTOResponse carries the one-time invite token. Only its hash is
+// stored, so it cannot be shown again; resending issues a new one.
+type InviteStaffDTOResponse struct {
+	Staff       StaffDTOResponse `json:"staff"`
+	InviteToken string           `json:"invite_token"`
+}
+
+type StaffLoginDTOResponse struct {
+	Staff StaffDTOResponse `json:"staff"`
+	Token string           `json:"token"`
+}
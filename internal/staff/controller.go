@@ -0,0 +1,275 @@
+package staff
+
+import (
+	"errors"
+	"net/http"
+	"shop-near-u/internal/middlewares"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Controller struct {
+	service *Service
+}
+
+func NewController(s *Service) *Controller {
+	return &Controller{service: s}
+}
+
+func (ctrl *Controller) InviteStaff(c *gin.Context) {
+	var dto InviteStaffDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	shop, ok := shopFromContext(c)
+	if !ok {
+		return
+	}
+
+	staff, token, err := ctrl.service.Invite(shop.ID, &dto)
+	if err != nil {
+		staffErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Staff member invited successfully", InviteStaffDTOResponse{
+		Staff:       toStaffResponse(*staff),
+		InviteToken: token,
+	})
+}
+
+func (ctrl *Controller) GetShopStaff(c *gin.Context) {
+	shop, ok := shopFromContext(c)
+	if !ok {
+		return
+	}
+
+	members, err := ctrl.service.GetShopStaff(shop.ID)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := make([]StaffDTOResponse, 0, len(members))
+	for _, staff := range members {
+		response = append(response, toStaffResponse(staff))
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Staff retrieved successfully", response)
+}
+
+func (ctrl *Controller) UpdatePermissions(c *gin.Context) {
+	staffID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid staff ID")
+		return
+	}
+
+	var dto UpdateStaffPermissionsDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	shop, ok := shopFromContext(c)
+	if !ok {
+		return
+	}
+
+	staff, err := ctrl.service.UpdatePermissions(shop.ID, staffID, dto.Permissions)
+	if err != nil {
+		staffErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Staff permissions updated successfully", toStaffResponse(*staff))
+}
+
+func (ctrl *Controller) ResendInvite(c *gin.Context) {
+	staffID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid staff ID")
+		return
+	}
+
+	shop, ok := shopFromContext(c)
+	if !ok {
+		return
+	}
+
+	staff, token, err := ctrl.service.ResendInvite(shop.ID, staffID)
+	if err != nil {
+		staffErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invite renewed successfully", InviteStaffDTOResponse{
+		Staff:       toStaffResponse(*staff),
+		InviteToken: token,
+	})
+}
+
+func (ctrl *Controller) RemoveStaff(c *gin.Context) {
+	staffID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid staff ID")
+		return
+	}
+
+	shop, ok := shopFromContext(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.RemoveStaff(shop.ID, staffID); err != nil {
+		staffErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Staff member removed successfully", nil)
+}
+
+func (ctrl *Controller) AcceptInvite(c *gin.Context) {
+	var dto AcceptInviteDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	staff, err := ctrl.service.AcceptInvite(&dto)
+	if err != nil {
+		staffErrorResponse(c, err)
+		return
+	}
+
+	ctrl.respondWithSession(c, staff, "Invite accepted successfully")
+}
+
+func (ctrl *Controller) Login(c *gin.Context) {
+	var dto StaffLoginDTORequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	staff, err := ctrl.service.Authenticate(&dto)
+	if err != nil {
+		staffErrorResponse(c, err)
+		return
+	}
+
+	ctrl.respondWithSession(c, staff, "Staff logged in successfully")
+}
+
+func (ctrl *Controller) Logout(c *gin.Context) {
+	utils.SetCookie("", -1, c)
+
+	utils.SuccessResponse(c, http.StatusOK, "Successfully logged out", nil)
+}
+
+func (ctrl *Controller) Me(c *gin.Context) {
+	staffInterface, exists := c.Get("staff")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	staff, ok := staffInterface.(models.ShopStaff)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, "failed to parse staff data")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Staff profile retrieved successfully", toStaffResponse(staff))
+}
+
+func (ctrl *Controller) respondWithSession(c *gin.Context, staff *models.ShopStaff, message string) {
+	token, err := utils.GenerateAccessToken(staff.ID, models.RoleShopStaff)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, "failed to generate token")
+		return
+	}
+
+	utils.SetCookie(token, 3600*24*30, c)
+
+	utils.SuccessResponse(c, http.StatusOK, message, StaffLoginDTOResponse{
+		Staff: toStaffResponse(*staff),
+		Token: token,
+	})
+}
+
+func shopFromContext(c *gin.Context) (models.Shop, bool) {
+	shopInterface, exists := c.Get("shop")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		return models.Shop{}, false
+	}
+
+	shop, ok := shopInterface.(models.Shop)
+	if !ok {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, "failed to parse shop data")
+		return models.Shop{}, false
+	}
+	return shop, true
+}
+
+func toStaffResponse(staff models.ShopStaff) StaffDTOResponse {
+	status := StaffStatusInvited
+	if staff.AcceptedAt != nil {
+		status = StaffStatusActive
+	}
+
+	return StaffDTOResponse{
+		ID:              staff.ID,
+		ShopID:          staff.ShopID,
+		Email:           staff.Email,
+		Name:            staff.Name,
+		Permissions:     staff.PermissionList(),
+		Status:          status,
+		InviteExpiresAt: staff.InviteExpiresAt,
+	}
+}
+
+func staffErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrStaffNotFound):
+		utils.ErrorResponseSimple(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrStaffExists), errors.Is(err, ErrInviteAccepted):
+		utils.ErrorResponseSimple(c, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrInviteInvalid), errors.Is(err, ErrShopRequired):
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrInvalidCredentials):
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, err.Error())
+	default:
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
+	repo := NewRepository(db)
+	svc := NewService(repo)
+	ctrl := NewController(svc)
+
+	shopStaff := r.Group("/shop/staff")
+	shopStaff.Use(middlewares.RequireShopOwnerAuth(db))
+	{
+		shopStaff.POST("", ctrl.InviteStaff)
+		shopStaff.GET("", ctrl.GetShopStaff)
+		shopStaff.PUT("/:id", ctrl.UpdatePermissions)
+		shopStaff.DELETE("/:id", ctrl.RemoveStaff)
+		shopStaff.POST("/:id/resend", ctrl.ResendInvite)
+	}
+
+	staff := r.Group("/staff")
+	{
+		staff.POST("/accept", ctrl.AcceptInvite)
+		staff.POST("/login", ctrl.Login)
+		staff.POST("/logout", middlewares.RequireStaffAuth(db), ctrl.Logout)
+		staff.GET("/me", middlewares.RequireStaffAuth(db), ctrl.Me)
+	}
+}
@@ -0,0 +1,76 @@
+package staff
+
+import (
+	"shop-near-u/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	DB *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{DB: db}
+}
+
+func (r *Repository) Create(staff *models.ShopStaff) error {
+	return r.DB.Create(staff).Error
+}
+
+func (r *Repository) Save(staff *models.ShopStaff) error {
+	return r.DB.Save(staff).Error
+}
+
+func (r *Repository) GetByShopID(shopID uint) ([]models.ShopStaff, error) {
+	var staff []models.ShopStaff
+	err := r.DB.Where("shop_id = ? AND removed_at IS NULL", shopID).Order("created_at ASC").Find(&staff).Error
+	return staff, err
+}
+
+func (r *Repository) GetByID(shopID uint, staffID uint) (*models.ShopStaff, error) {
+	var staff models.ShopStaff
+	if err := r.DB.Where("id = ? AND shop_id = ? AND removed_at IS NULL", staffID, shopID).First(&staff).Error; err != nil {
+		return nil, err
+	}
+	return &staff, nil
+}
+
+// FindByShopAndEmail returns nil without an error when no such staff exists.
+// Removed staff are included so a re-invite reuses their row.
+func (r *Repository) FindByShopAndEmail(shopID uint, email string) (*models.ShopStaff, error) {
+	var staff models.ShopStaff
+	if err := r.DB.Where("shop_id = ? AND email = ?", shopID, email).Limit(1).Find(&staff).Error; err != nil {
+		return nil, err
+	}
+	if staff.ID == 0 {
+		return nil, nil
+	}
+	return &staff, nil
+}
+
+func (r *Repository) FindPendingInvite(email string, tokenHash string) (*models.ShopStaff, error) {
+	var staff models.ShopStaff
+	err := r.DB.Where("email = ? AND invite_token_hash = ? AND accepted_at IS NULL AND removed_at IS NULL", email, tokenHash).First(&staff).Error
+	if err != nil {
+		return nil, err
+	}
+	return &staff, nil
+}
+
+func (r *Repository) FindActiveByEmail(email string) ([]models.ShopStaff, error) {
+	var staff []models.ShopStaff
+	err := r.DB.Where("email = ? AND accepted_at IS NOT NULL AND removed_at IS NULL", email).Find(&staff).Error
+	return staff, err
+}
+
+// Remove revokes the staff member's access but keeps the row for the
+// inventory log.
+func (r *Repository) Remove(staff *models.ShopStaff, at time.Time) error {
+	return r.DB.Model(staff).Updates(map[string]interface{}{
+		"removed_at":        at,
+		"invite_token_hash": "",
+		"invite_expires_at": nil,
+	}).Error
+}
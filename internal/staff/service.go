@@ -0,0 +1,238 @@
+package staff
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// inviteValidity is how long an invite token can be accepted.
+const inviteValidity = 7 * 24 * time.Hour
+
+var (
+	ErrStaffNotFound      = errors.New("staff member not found")
+	ErrStaffExists        = errors.New("this email is already a staff member of the shop")
+	ErrInviteInvalid      = errors.New("invite is invalid or has expired")
+	ErrInviteAccepted     = errors.New("invite has already been accepted")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrShopRequired       = errors.New("this email works at several shops, please provide shop_id")
+)
+
+type Service struct {
+	repository *Repository
+}
+
+func NewService(r *Repository) *Service {
+	return &Service{repository: r}
+}
+
+// Invite creates a pending staff member, or renews the invite of one who has
+// not accepted yet. It returns the plain invite token for the owner to pass on.
+func (s *Service) Invite(shopID uint, dto *InviteStaffDTORequest) (*models.ShopStaff, string, error) {
+	email := normalizeEmail(dto.Email)
+
+	staff, err := s.repository.FindByShopAndEmail(shopID, email)
+	if err != nil {
+		return nil, "", err
+	}
+	if staff != nil && staff.RemovedAt != nil {
+		reinstate(staff)
+	}
+	if staff != nil && staff.AcceptedAt != nil {
+		return nil, "", ErrStaffExists
+	}
+
+	token, hash, err := newInviteToken()
+	if err != nil {
+		return nil, "", err
+	}
+	expiresAt := time.Now().Add(inviteValidity)
+
+	if staff == nil {
+		staff = &models.ShopStaff{ShopID: shopID, Email: email}
+	}
+	staff.Name = dto.Name
+	staff.Permissions = joinPermissions(dto.Permissions)
+	staff.InviteTokenHash = hash
+	staff.InviteExpiresAt = &expiresAt
+
+	if staff.ID == 0 {
+		err = s.repository.Create(staff)
+	} else {
+		err = s.repository.Save(staff)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return staff, token, nil
+}
+
+// ResendInvite replaces the token of a pending invite and restarts its expiry.
+func (s *Service) ResendInvite(shopID uint, staffID uint) (*models.ShopStaff, string, error) {
+	staff, err := s.getStaff(shopID, staffID)
+	if err != nil {
+		return nil, "", err
+	}
+	if staff.AcceptedAt != nil {
+		return nil, "", ErrInviteAccepted
+	}
+
+	token, hash, err := newInviteToken()
+	if err != nil {
+		return nil, "", err
+	}
+	expiresAt := time.Now().Add(inviteValidity)
+	staff.InviteTokenHash = hash
+	staff.InviteExpiresAt = &expiresAt
+
+	if err := s.repository.Save(staff); err != nil {
+		return nil, "", err
+	}
+	return staff, token, nil
+}
+
+func (s *Service) GetShopStaff(shopID uint) ([]models.ShopStaff, error) {
+	return s.repository.GetByShopID(shopID)
+}
+
+func (s *Service) UpdatePermissions(shopID uint, staffID uint, permissions []string) (*models.ShopStaff, error) {
+	staff, err := s.getStaff(shopID, staffID)
+	if err != nil {
+		return nil, err
+	}
+
+	staff.Permissions = joinPermissions(permissions)
+	if err := s.repository.Save(staff); err != nil {
+		return nil, err
+	}
+	return staff, nil
+}
+
+// RemoveStaff revokes the staff member's access. The record stays so past
+// inventory changes remain attributed to them.
+func (s *Service) RemoveStaff(shopID uint, staffID uint) error {
+	staff, err := s.getStaff(shopID, staffID)
+	if err != nil {
+		return err
+	}
+	return s.repository.Remove(staff, time.Now().UTC())
+}
+
+// reinstate turns a removed staff member back into a fresh, unaccepted
+// invite: they must accept again and choose a new password.
+func reinstate(staff *models.ShopStaff) {
+	staff.RemovedAt = nil
+	staff.AcceptedAt = nil
+	staff.Password = ""
+}
+
+// AcceptInvite sets the staff member's password and activates the account.
+func (s *Service) AcceptInvite(dto *AcceptInviteDTORequest) (*models.ShopStaff, error) {
+	staff, err := s.repository.FindPendingInvite(normalizeEmail(dto.Email), hashToken(dto.Token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteInvalid
+		}
+		return nil, err
+	}
+	if staff.InviteExpiresAt == nil || time.Now().After(*staff.InviteExpiresAt) {
+		return nil, ErrInviteInvalid
+	}
+
+	password, err := utils.HashPassword(dto.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	staff.Password = password
+	if dto.Name != "" {
+		staff.Name = dto.Name
+	}
+	staff.AcceptedAt = &now
+	staff.InviteTokenHash = ""
+	staff.InviteExpiresAt = nil
+
+	if err := s.repository.Save(staff); err != nil {
+		return nil, err
+	}
+	return staff, nil
+}
+
+func (s *Service) Authenticate(dto *StaffLoginDTORequest) (*models.ShopStaff, error) {
+	accounts, err := s.repository.FindActiveByEmail(normalizeEmail(dto.Email))
+	if err != nil {
+		return nil, err
+	}
+
+	var staff *models.ShopStaff
+	for i := range accounts {
+		if dto.ShopID != 0 && accounts[i].ShopID != dto.ShopID {
+			continue
+		}
+		if staff != nil {
+			return nil, ErrShopRequired
+		}
+		staff = &accounts[i]
+	}
+	if staff == nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := utils.CheckPasswordHash(dto.Password, staff.Password); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return staff, nil
+}
+
+func (s *Service) getStaff(shopID uint, staffID uint) (*models.ShopStaff, error) {
+	staff, err := s.repository.GetByID(shopID, staffID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStaffNotFound
+		}
+		return nil, err
+	}
+	return staff, nil
+}
+
+// joinPermissions stores the granted permissions once each, in a fixed order.
+func joinPermissions(permissions []string) string {
+	granted := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		granted[p] = true
+	}
+
+	ordered := make([]string, 0, len(granted))
+	for _, p := range models.StaffPermissions {
+		if granted[p] {
+			ordered = append(ordered, p)
+		}
+	}
+	return strings.Join(ordered, ",")
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func newInviteToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(buf)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
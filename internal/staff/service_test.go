@@ -0,0 +1,47 @@
+package staff
+
+import (
+	"shop-near-u/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinPermissions(t *testing.T) {
+	joined := joinPermissions([]string{models.PermissionViewOrders, models.PermissionManageProducts, models.PermissionViewOrders})
+	assert.Equal(t, "manage_products,view_orders", joined)
+
+	staff := models.ShopStaff{Permissions: joined}
+	assert.True(t, staff.HasPermission(models.PermissionManageProducts))
+	assert.False(t, staff.HasPermission(models.PermissionToggleStatus))
+
+	assert.Empty(t, (&models.ShopStaff{}).PermissionList())
+}
+
+func TestInviteToken(t *testing.T) {
+	token, hash, err := newInviteToken()
+	require.NoError(t, err)
+	assert.Len(t, token, 64)
+	assert.Equal(t, hashToken(token), hash)
+	assert.NotEqual(t, token, hash)
+
+	other, _, err := newInviteToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	assert.Equal(t, "clerk@example.com", normalizeEmail("  Clerk@Example.COM "))
+}
+
+func TestReinstate(t *testing.T) {
+	now := time.Now()
+	staff := models.ShopStaff{Password: "hash", AcceptedAt: &now, RemovedAt: &now}
+
+	reinstate(&staff)
+	assert.Nil(t, staff.RemovedAt)
+	assert.Nil(t, staff.AcceptedAt)
+	assert.Empty(t, staff.Password)
+}
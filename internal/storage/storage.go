@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+var (
+	ErrNotFound       = errors.New("object not found")
+	ErrInvalidKey     = errors.New("invalid object key")
+	ErrUnknownBackend = errors.New("unknown storage backend")
+)
+
+// Object is a stored file opened for reading. The caller closes Body.
+type Object struct {
+	Body        io.ReadCloser
+	Size        int64
+	ContentType string
+}
+
+// Storage keeps uploaded files under slash-separated keys such as
+// "images/ab/abcdef.jpg".
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (*Object, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Open builds the named backend from its environment configuration.
+func Open(name string) (Storage, error) {
+	switch name {
+	case LocalBackendName:
+		dir := os.Getenv("STORAGE_LOCAL_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		return NewLocalStorage(dir)
+	case S3BackendName:
+		return NewS3Storage(S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		})
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, name)
+	}
+}
+
+// New returns the backend named by STORAGE_BACKEND, defaulting to the local
+// filesystem.
+func New() (Storage, error) {
+	name := os.Getenv("STORAGE_BACKEND")
+	if name == "" {
+		name = LocalBackendName
+	}
+	return Open(name)
+}
+
+// cleanKey rejects keys that are empty, absolute or try to climb out of the
+// storage root.
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned != key || cleaned == "." || strings.HasPrefix(cleaned, "../") || cleaned == ".." {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
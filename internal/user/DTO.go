@@ -15,3 +15,14 @@ type ChangePasswordDTO struct {
 	OldPassword string `json:"old_password" binding:"required"`
 	NewPassword string `json:"new_password" binding:"required,min=6"`
 }
+
+// AddressDTORequest creates or replaces a saved address. Latitude and
+// Longitude must be sent together or both omitted, in which case the address
+// is geocoded.
+type AddressDTORequest struct {
+	Label     string   `json:"label" binding:"required,max=50"`
+	Address   string   `json:"address" binding:"required,max=255"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,gte=-180,lte=180"`
+	IsDefault bool     `json:"is_default"`
+}
@@ -0,0 +1,147 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"shop-near-u/internal/models"
+	"shop-near-u/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *Controller) GetAddresses(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	addresses, err := ctrl.service.GetAddresses(u.ID)
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Addresses retrieved successfully", addresses)
+}
+
+func (ctrl *Controller) AddAddress(c *gin.Context) {
+	var addressDTO AddressDTORequest
+	if err := c.ShouldBindJSON(&addressDTO); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	address, err := ctrl.service.AddAddress(c.Request.Context(), u.ID, &addressDTO)
+	if err != nil {
+		addressErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Address saved successfully", address)
+}
+
+func (ctrl *Controller) UpdateAddress(c *gin.Context) {
+	addressID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid address ID")
+		return
+	}
+
+	var addressDTO AddressDTORequest
+	if err := c.ShouldBindJSON(&addressDTO); err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	address, err := ctrl.service.UpdateAddress(c.Request.Context(), u.ID, addressID, &addressDTO)
+	if err != nil {
+		addressErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Address updated successfully", address)
+}
+
+func (ctrl *Controller) SetDefaultAddress(c *gin.Context) {
+	addressID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid address ID")
+		return
+	}
+
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	address, err := ctrl.service.SetDefaultAddress(u.ID, addressID)
+	if err != nil {
+		addressErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Default address updated successfully", address)
+}
+
+func (ctrl *Controller) DeleteAddress(c *gin.Context) {
+	addressID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, "invalid address ID")
+		return
+	}
+
+	user, exists := c.Get("user")
+	if !exists {
+		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	u := user.(models.User)
+
+	if err := ctrl.service.DeleteAddress(u.ID, addressID); err != nil {
+		addressErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Address deleted successfully", nil)
+}
+
+func addressErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrAddressNotFound):
+		utils.ErrorResponseSimple(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrAddressNotGeocoded):
+		utils.ErrorResponseSimple(c, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, ErrIncompleteCoordinates):
+		utils.ErrorResponseSimple(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponseSimple(c, http.StatusInternalServerError, err.Error())
+	}
+}
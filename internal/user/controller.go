@@ -1,7 +1,9 @@
 package user
 
 import (
+	"errors"
 	"net/http"
+	"shop-near-u/internal/geocoding"
 	"shop-near-u/internal/middlewares"
 	"shop-near-u/internal/models"
 	"shop-near-u/internal/utils"
@@ -60,6 +62,10 @@ func (ctrl *Controller) Login(c *gin.Context) {
 
 	user, err := ctrl.service.AuthenticateUser(loginDTO.Email, loginDTO.Password)
 	if err != nil {
+		if errors.Is(err, ErrAccountSuspended) {
+			utils.ErrorResponseSimple(c, http.StatusForbidden, err.Error())
+			return
+		}
 		utils.ErrorResponseSimple(c, http.StatusUnauthorized, "invalid credentials")
 		return
 	}
@@ -151,9 +157,9 @@ func (ctrl *Controller) DeleteAccount(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Account deleted successfully", nil)
 }
 
-func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
+func RegisterRoutes(r *gin.Engine, db *gorm.DB, geocoder geocoding.Geocoder) {
 	repo := NewRepository(db)
-	svc := NewService(repo)
+	svc := NewService(repo, geocoder)
 	ctrl := NewController(svc)
 
 	users := r.Group("/auth")
@@ -165,4 +171,13 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
 		users.POST("/change-password", middlewares.RequireUserAuth(db), ctrl.ChangePassword)
 		users.DELETE("/delete-account", middlewares.RequireUserAuth(db), ctrl.DeleteAccount)
 	}
+
+	addresses := r.Group("/addresses", middlewares.RequireUserAuth(db))
+	{
+		addresses.GET("", ctrl.GetAddresses)
+		addresses.POST("", ctrl.AddAddress)
+		addresses.PUT("/:id", ctrl.UpdateAddress)
+		addresses.DELETE("/:id", ctrl.DeleteAddress)
+		addresses.POST("/:id/default", ctrl.SetDefaultAddress)
+	}
 }
@@ -38,6 +38,205 @@ func (r *Repository) UpdateUser(user *models.User) error {
 	return r.DB.Save(user).Error
 }
 
+// DeleteUser removes the account together with its saved addresses and cart
+// lines in one transaction.
 func (r *Repository) DeleteUser(id uint) error {
-	return r.DB.Delete(&models.User{}, id).Error
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Where("user_id = ?", id).Delete(&models.UserAddress{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("user_id = ?", id).Delete(&models.CartItem{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(&models.User{}, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (r *Repository) GetAddresses(userID uint) ([]models.UserAddress, error) {
+	var addresses []models.UserAddress
+	if err := r.DB.Where("user_id = ?", userID).Order("is_default DESC, created_at DESC").Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+func (r *Repository) GetAddress(userID uint, addressID uint) (*models.UserAddress, error) {
+	var address models.UserAddress
+	if err := r.DB.Where("id = ? AND user_id = ?", addressID, userID).First(&address).Error; err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// CreateAddress saves a new address. The user's first address always becomes
+// the default.
+func (r *Repository) CreateAddress(address *models.UserAddress) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	var count int64
+	if err := tx.Model(&models.UserAddress{}).Where("user_id = ?", address.UserID).Count(&count).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	makeDefault := address.IsDefault || count == 0
+	address.IsDefault = false
+	if err := tx.Create(address).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if makeDefault {
+		if err := setDefaultAddress(tx, address); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// UpdateAddress saves changes to an address, keeping the user's location in
+// step when it is the default.
+func (r *Repository) UpdateAddress(address *models.UserAddress, makeDefault bool) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Model(address).Updates(map[string]interface{}{
+		"label":     address.Label,
+		"address":   address.Address,
+		"latitude":  address.Latitude,
+		"longitude": address.Longitude,
+	}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if makeDefault || address.IsDefault {
+		if err := setDefaultAddress(tx, address); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (r *Repository) SetDefaultAddress(address *models.UserAddress) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := setDefaultAddress(tx, address); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// DeleteAddress removes an address. When it was the default, the most recently
+// added remaining address takes its place, or the user's location is cleared.
+func (r *Repository) DeleteAddress(address *models.UserAddress) error {
+	tx := r.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Delete(&models.UserAddress{}, address.ID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if address.IsDefault {
+		var next models.UserAddress
+		err := tx.Where("user_id = ?", address.UserID).Order("created_at DESC").Limit(1).Find(&next).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if next.ID != 0 {
+			err = setDefaultAddress(tx, &next)
+		} else {
+			err = tx.Model(&models.User{}).Where("id = ?", address.UserID).Updates(map[string]interface{}{
+				"latitude":  0,
+				"longitude": 0,
+			}).Error
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// setDefaultAddress marks address as the user's only default and copies its
+// coordinates onto the user row.
+func setDefaultAddress(tx *gorm.DB, address *models.UserAddress) error {
+	if err := tx.Model(&models.UserAddress{}).
+		Where("user_id = ? AND id <> ? AND is_default", address.UserID, address.ID).
+		Update("is_default", false).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.UserAddress{}).Where("id = ?", address.ID).Update("is_default", true).Error; err != nil {
+		return err
+	}
+	address.IsDefault = true
+
+	return tx.Model(&models.User{}).Where("id = ?", address.UserID).Updates(map[string]interface{}{
+		"latitude":  address.Latitude,
+		"longitude": address.Longitude,
+	}).Error
 }
@@ -1,16 +1,29 @@
 package user
 
 import (
+	"context"
+	"errors"
+	"shop-near-u/internal/geocoding"
 	"shop-near-u/internal/models"
 	"shop-near-u/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAccountSuspended      = errors.New("account suspended")
+	ErrAddressNotFound       = errors.New("address not found")
+	ErrAddressNotGeocoded    = errors.New("could not find coordinates for the address, please provide latitude and longitude")
+	ErrIncompleteCoordinates = errors.New("latitude and longitude must be provided together")
 )
 
 type Service struct {
 	repository *Repository
+	geocoder   geocoding.Geocoder
 }
 
-func NewService(r *Repository) *Service {
-	return &Service{repository: r}
+func NewService(r *Repository, g geocoding.Geocoder) *Service {
+	return &Service{repository: r, geocoder: g}
 }
 
 func (s *Service) RegisterUser(userRegistrationDTO *UserRegisterDTO) (*models.User, error) {
@@ -76,5 +89,104 @@ func (s *Service) AuthenticateUser(email, password string) (*models.User, error)
 	if err := utils.CheckPasswordHash(password, user.Password); err != nil {
 		return nil, err
 	}
+	if user.SuspendedAt != nil {
+		return nil, ErrAccountSuspended
+	}
 	return user, nil
 }
+
+func (s *Service) GetAddresses(userID uint) ([]models.UserAddress, error) {
+	return s.repository.GetAddresses(userID)
+}
+
+func (s *Service) AddAddress(ctx context.Context, userID uint, dto *AddressDTORequest) (*models.UserAddress, error) {
+	if err := s.resolveCoordinates(ctx, dto); err != nil {
+		return nil, err
+	}
+
+	address := &models.UserAddress{
+		UserID:    userID,
+		Label:     dto.Label,
+		Address:   dto.Address,
+		Latitude:  *dto.Latitude,
+		Longitude: *dto.Longitude,
+		IsDefault: dto.IsDefault,
+	}
+	if err := s.repository.CreateAddress(address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// UpdateAddress replaces an address. Passing is_default=false does not unset
+// the current default; pick another address as default instead.
+func (s *Service) UpdateAddress(ctx context.Context, userID uint, addressID uint, dto *AddressDTORequest) (*models.UserAddress, error) {
+	address, err := s.getAddress(userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.resolveCoordinates(ctx, dto); err != nil {
+		return nil, err
+	}
+
+	address.Label = dto.Label
+	address.Address = dto.Address
+	address.Latitude = *dto.Latitude
+	address.Longitude = *dto.Longitude
+	if err := s.repository.UpdateAddress(address, dto.IsDefault); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+func (s *Service) SetDefaultAddress(userID uint, addressID uint) (*models.UserAddress, error) {
+	address, err := s.getAddress(userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.SetDefaultAddress(address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+func (s *Service) DeleteAddress(userID uint, addressID uint) error {
+	address, err := s.getAddress(userID, addressID)
+	if err != nil {
+		return err
+	}
+	return s.repository.DeleteAddress(address)
+}
+
+func (s *Service) getAddress(userID uint, addressID uint) (*models.UserAddress, error) {
+	address, err := s.repository.GetAddress(userID, addressID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAddressNotFound
+		}
+		return nil, err
+	}
+	return address, nil
+}
+
+// resolveCoordinates geocodes the address when no coordinates were supplied.
+func (s *Service) resolveCoordinates(ctx context.Context, dto *AddressDTORequest) error {
+	if (dto.Latitude == nil) != (dto.Longitude == nil) {
+		return ErrIncompleteCoordinates
+	}
+	if dto.Latitude != nil {
+		return nil
+	}
+
+	result, err := s.geocoder.Geocode(ctx, dto.Address)
+	if err != nil {
+		if errors.Is(err, geocoding.ErrNotFound) {
+			return ErrAddressNotGeocoded
+		}
+		return err
+	}
+	dto.Latitude, dto.Longitude = &result.Latitude, &result.Longitude
+	return nil
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/restayway/gogis"
+)
+
+const GeoJSONMediaType = "application/geo+json"
+
+type GeoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   GeoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// WantsGeoJSON reports whether the client asked for GeoJSON, either with
+// format=geojson or an Accept header naming application/geo+json.
+func WantsGeoJSON(c *gin.Context) bool {
+	if strings.EqualFold(c.Query("format"), "geojson") {
+		return true
+	}
+	return strings.Contains(c.GetHeader("Accept"), GeoJSONMediaType)
+}
+
+// NewPointFeature builds a Point feature. GeoJSON orders coordinates as
+// [longitude, latitude].
+func NewPointFeature(id interface{}, point gogis.Point, properties map[string]interface{}) GeoJSONFeature {
+	return GeoJSONFeature{
+		Type: "Feature",
+		ID:   id,
+		Geometry: GeoJSONGeometry{
+			Type:        "Point",
+			Coordinates: []float64{point.Lng, point.Lat},
+		},
+		Properties: properties,
+	}
+}
+
+func NewFeatureCollection(features []GeoJSONFeature) GeoJSONFeatureCollection {
+	if features == nil {
+		features = []GeoJSONFeature{}
+	}
+	return GeoJSONFeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+// ToProperties flattens a struct into a property map using its JSON tags.
+func ToProperties(value interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return properties
+	}
+	_ = json.Unmarshal(data, &properties)
+	return properties
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/restayway/gogis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPointFeatureEncodesLngLat(t *testing.T) {
+	feature := NewPointFeature(7, gogis.Point{Lng: 80.2376, Lat: 13.0743}, ToProperties(struct {
+		Name string `json:"name"`
+	}{Name: "Corner Store"}))
+
+	data, err := json.Marshal(NewFeatureCollection([]GeoJSONFeature{feature}))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"type": "FeatureCollection",
+		"features": [{
+			"type": "Feature",
+			"id": 7,
+			"geometry": {"type": "Point", "coordinates": [80.2376, 13.0743]},
+			"properties": {"name": "Corner Store"}
+		}]
+	}`, string(data))
+}
+
+func TestWantsGeoJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newContext := func(target string, accept string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", target, nil)
+		if accept != "" {
+			c.Request.Header.Set("Accept", accept)
+		}
+		return c
+	}
+
+	assert.True(t, WantsGeoJSON(newContext("/shops?format=geojson", "")))
+	assert.True(t, WantsGeoJSON(newContext("/shops", "application/geo+json")))
+	assert.False(t, WantsGeoJSON(newContext("/shops", "application/json")))
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidBarcode = errors.New("barcode must be a valid EAN-8, UPC-A, EAN-13 or GTIN-14")
+
+// NormalizeGTIN validates an EAN-8, UPC-A, EAN-13 or GTIN-14 barcode and
+// returns it as a zero-padded 14-digit GTIN, so the UPC-A "036000291452" and
+// the EAN-13 "0036000291452" compare equal. Spaces and hyphens are ignored.
+func NormalizeGTIN(code string) (string, error) {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code))
+
+	switch len(digits) {
+	case 8, 12, 13, 14:
+	default:
+		return "", ErrInvalidBarcode
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", ErrInvalidBarcode
+		}
+	}
+
+	gtin := strings.Repeat("0", 14-len(digits)) + digits
+	if gtinCheckDigit(gtin[:13]) != gtin[13] {
+		return "", ErrInvalidBarcode
+	}
+	return gtin, nil
+}
+
+// gtinCheckDigit computes the GS1 mod-10 check digit: digits are weighted
+// 3 and 1 alternately, starting with 3 at the rightmost position.
+func gtinCheckDigit(body string) byte {
+	sum := 0
+	for i := len(body) - 1; i >= 0; i-- {
+		digit := int(body[i] - '0')
+		if (len(body)-1-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeGTIN(t *testing.T) {
+	cases := map[string]string{
+		"96385074":        "00000096385074", // EAN-8
+		"036000291452":    "00036000291452", // UPC-A
+		"0036000291452":   "00036000291452", // same product as EAN-13
+		"8901262150064":   "08901262150064", // EAN-13
+		"8 901262-150064": "08901262150064",
+		"10036000291459":  "10036000291459", // GTIN-14 case pack
+	}
+	for input, want := range cases {
+		got, err := NormalizeGTIN(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, bad := range []string{"", "036000291453", "12345", "03600029145A", "123456789012345"} {
+		_, err := NormalizeGTIN(bad)
+		assert.ErrorIs(t, err, ErrInvalidBarcode, bad)
+	}
+}
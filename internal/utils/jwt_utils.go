@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"os"
+	"shop-near-u/internal/models"
 	"strconv"
 	"time"
 
@@ -22,6 +23,50 @@ func GenerateAccessToken(userID uint, role string) (string, error) {
 	return token.SignedString([]byte(os.Getenv("SECRET_KEY")))
 }
 
+// GenerateShopAccessToken issues a shop owner token for shopID. When the shop
+// was selected through a merchant account the merchant ID is carried along so
+// the owner can switch branches without logging in again.
+func GenerateShopAccessToken(shopID uint, merchantID uint) (string, error) {
+	claims := jwt.MapClaims{
+		"sub":  shopID,
+		"role": models.RoleShopOwner,
+		"exp":  time.Now().Add(7 * 24 * time.Hour).Unix(),
+	}
+	if merchantID != 0 {
+		claims["merchant"] = merchantID
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	return token.SignedString([]byte(os.Getenv("SECRET_KEY")))
+}
+
+// ParseMerchantID returns the merchant claim of a valid token, or 0 when the
+// token has none.
+func ParseMerchantID(tokenString string) (uint, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte(os.Getenv("SECRET_KEY")), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return 0, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return 0, jwt.ErrTokenInvalidClaims
+	}
+
+	merchantVal, ok := claims["merchant"]
+	if !ok {
+		return 0, nil
+	}
+	merchantID, ok := merchantVal.(float64)
+	if !ok || merchantID < 0 {
+		return 0, jwt.ErrTokenInvalidClaims
+	}
+	return uint(merchantID), nil
+}
+
 func ParseToken(tokenString string) (int64, string, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		return []byte(os.Getenv("SECRET_KEY")), nil
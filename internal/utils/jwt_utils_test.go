@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"shop-near-u/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateShopAccessTokenCarriesMerchant(t *testing.T) {
+	t.Setenv("SECRET_KEY", "test-secret")
+
+	token, err := GenerateShopAccessToken(12, 3)
+	require.NoError(t, err)
+
+	shopID, role, err := ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), shopID)
+	assert.Equal(t, models.RoleShopOwner, role)
+
+	merchantID, err := ParseMerchantID(token)
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), merchantID)
+}
+
+func TestParseMerchantIDWithoutClaim(t *testing.T) {
+	t.Setenv("SECRET_KEY", "test-secret")
+
+	token, err := GenerateAccessToken(12, models.RoleShopOwner)
+	require.NoError(t, err)
+
+	merchantID, err := ParseMerchantID(token)
+	require.NoError(t, err)
+	assert.Zero(t, merchantID)
+}
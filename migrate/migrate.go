@@ -1,9 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"shop-near-u/internal/models"
+	"strings"
 
 	_ "github.com/joho/godotenv/autoload"
 	"gorm.io/driver/postgres"
@@ -32,18 +34,127 @@ func Migrate() {
 	db.Exec("CREATE EXTENSION IF NOT EXISTS postgis;")
 	// Migrate the schema
 	err = db.AutoMigrate(&models.User{})
+	err = db.AutoMigrate(&models.Category{})
+	err = db.AutoMigrate(&models.UserAddress{})
+	err = db.AutoMigrate(&models.Merchant{})
 	err = db.AutoMigrate(&models.Shop{})
+	err = db.AutoMigrate(&models.ShopAddressHistory{})
+	// Images were once deduplicated across uploaders, with unique checksums
+	// and keys; now every uploader gets its own row for the shared files.
+	for _, index := range []string{"idx_images_checksum", "idx_images_key"} {
+		if db.Migrator().HasIndex(&models.Image{}, index) {
+			err = db.Migrator().DropIndex(&models.Image{}, index)
+		}
+	}
+	err = db.AutoMigrate(&models.Image{})
+	err = db.AutoMigrate(&models.ImageVariant{})
+	err = db.AutoMigrate(&models.ShopImage{})
+	err = db.AutoMigrate(&models.DeliveryFeeTier{})
+	err = db.AutoMigrate(&models.ShopOpeningHours{})
+	err = db.AutoMigrate(&models.ShopHoursException{})
+	// The description column was originally created with a typo
+	if db.Migrator().HasColumn("catalog_products", "desciption") {
+		err = db.Migrator().RenameColumn("catalog_products", "desciption", "description")
+	}
 	err = db.AutoMigrate(&models.CatalogProduct{})
+	err = db.AutoMigrate(&models.CatalogProductRevision{})
+	err = db.AutoMigrate(&models.CatalogBarcode{})
+	err = db.AutoMigrate(&models.CatalogRedirect{})
 	err = db.AutoMigrate(&models.ShopProduct{})
+	err = db.AutoMigrate(&models.ShopStaff{})
+	err = db.AutoMigrate(&models.InventoryChange{})
 	err = db.AutoMigrate(&models.ShopSubscription{})
+	err = db.AutoMigrate(&models.Order{})
+	err = db.AutoMigrate(&models.OrderItem{})
+	err = db.AutoMigrate(&models.CartItem{})
+	err = db.AutoMigrate(&models.StockReservation{})
 
 	if err != nil {
 		panic("failed to migrate database")
 	}
 
+	if err := backfillCatalogMatchKeys(db); err != nil {
+		panic("failed to backfill catalog match keys")
+	}
+
+	if err := backfillCategories(db); err != nil {
+		panic("failed to backfill categories")
+	}
+
+	// Rejected submissions no longer hold on to their barcodes
+	if err := db.Where("catalog_product_id IN (?)", db.Model(&models.CatalogProduct{}).Select("id").Where("status = ?", models.CatalogStatusRejected)).
+		Delete(&models.CatalogBarcode{}).Error; err != nil {
+		panic("failed to release barcodes of rejected catalog products")
+	}
+
 	fmt.Println("Database migration completed successfully.")
 }
 
+// backfillCatalogMatchKeys fills MatchKey for catalog products created before
+// duplicate detection existed.
+func backfillCatalogMatchKeys(db *gorm.DB) error {
+	var products []models.CatalogProduct
+	return db.Where("match_key IS NULL OR match_key = ''").FindInBatches(&products, 500, func(tx *gorm.DB, batch int) error {
+		for i := range products {
+			if err := tx.Model(&products[i]).Update("match_key", models.CatalogMatchKey(products[i].Name, products[i].Brand, products[i].Category)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// backfillCategories moves the free-text catalog categories and shop types
+// into the category tree. Spellings that share a slug, such as "Dairy" and
+// "dairy", become one root category; admins can re-parent them afterwards.
+func backfillCategories(db *gorm.DB) error {
+	sources := []struct {
+		table  string
+		column string
+	}{
+		{"catalog_products", "category"},
+		{"shops", "type"},
+	}
+
+	for _, source := range sources {
+		var names []string
+		if err := db.Table(source.table).
+			Where("category_id IS NULL AND TRIM("+source.column+") <> ''").
+			Distinct(source.column).
+			Pluck(source.column, &names).Error; err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			slug := models.Slugify(name)
+			if slug == "" {
+				continue
+			}
+
+			var category models.Category
+			err := db.Where("slug = ?", slug).First(&category).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				category = models.Category{Name: strings.TrimSpace(name), Slug: slug}
+				if err := db.Create(&category).Error; err != nil {
+					return err
+				}
+				category.Path = models.CategoryPath("", category.ID)
+				err = db.Model(&category).Update("path", category.Path).Error
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := db.Table(source.table).
+				Where(source.column+" = ? AND category_id IS NULL", name).
+				Update("category_id", category.ID).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func main() {
 	Migrate()
 }